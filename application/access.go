@@ -0,0 +1,116 @@
+package application
+
+import (
+	"fmt"
+	"godtop/domain"
+	"path"
+)
+
+//roleLevels orders roles which include the roles below them, the agent role is outside of the order
+var roleLevels = map[string]int{
+	domain.AgentRole:    0,
+	domain.ViewerRole:   1,
+	domain.OperatorRole: 2,
+	domain.AdminRole:    3,
+}
+
+//AccessPolicy decides which roles principals have on the API and on containers
+type AccessPolicy struct {
+	Bindings []domain.RoleBinding
+}
+
+func NewAccessPolicy(bindings []domain.RoleBinding) (*AccessPolicy, error) {
+	for i, binding := range bindings {
+		if _, ok := roleLevels[binding.Role]; !ok {
+			return nil, fmt.Errorf("role binding %d: unknown role %q, expected viewer, operator, admin or agent", i, binding.Role)
+		}
+		if len(binding.Principals) == 0 {
+			return nil, fmt.Errorf("role binding %d: no principals", i)
+		}
+
+		patterns := append(append([]string{}, binding.Principals...), binding.Containers.Names...)
+		for _, pattern := range patterns {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("role binding %d: invalid pattern %q: %w", i, pattern, err)
+			}
+		}
+	}
+
+	return &AccessPolicy{Bindings: bindings}, nil
+}
+
+//Allows reports whether the principal has at least the required role on the whole API,
+//bindings scoped to containers only grant routes of a single container since global routes would expose all of them
+func (p *AccessPolicy) Allows(principal domain.Principal, required string) bool {
+	for _, binding := range p.Bindings {
+		if p.grants(binding, principal, required) && scopeMatches(binding.Containers, nil) {
+			return true
+		}
+	}
+
+	return false
+}
+
+//AllowsContainer reports whether the principal has at least the required role on the container,
+//a nil container is only allowed by bindings without a scope
+func (p *AccessPolicy) AllowsContainer(principal domain.Principal, required string, container *domain.ContainerDetails) bool {
+	for _, binding := range p.Bindings {
+		if p.grants(binding, principal, required) && scopeMatches(binding.Containers, container) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *AccessPolicy) grants(binding domain.RoleBinding, principal domain.Principal, required string) bool {
+	if !roleIncludes(binding.Role, required) {
+		return false
+	}
+
+	for _, pattern := range binding.Principals {
+		if matched, _ := path.Match(pattern, principal.Name); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+//roleIncludes reports whether the role grants the required one,
+//the agent role is granted by itself and by roles which may run actions, it grants no other role
+func roleIncludes(role string, required string) bool {
+	if required == domain.AgentRole {
+		return role == domain.AgentRole || roleLevels[role] >= roleLevels[domain.OperatorRole]
+	}
+
+	return role != domain.AgentRole && roleLevels[role] >= roleLevels[required]
+}
+
+func scopeMatches(scope domain.ContainerScope, container *domain.ContainerDetails) bool {
+	if len(scope.Names) == 0 && len(scope.Labels) == 0 {
+		return true
+	}
+	if container == nil {
+		return false
+	}
+
+	for key, value := range scope.Labels {
+		if container.Labels[key] != value {
+			return false
+		}
+	}
+
+	if len(scope.Names) == 0 {
+		return true
+	}
+	for _, pattern := range scope.Names {
+		for _, name := range container.Names {
+			if matched, _ := path.Match(pattern, name); matched {
+				return true
+			}
+		}
+	}
+
+	return false
+}
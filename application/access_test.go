@@ -0,0 +1,94 @@
+package application
+
+import (
+	"godtop/domain"
+	"testing"
+)
+
+func TestScopeMatches(t *testing.T) {
+	web := &domain.ContainerDetails{
+		Container: domain.Container{Names: []string{"web-1"}},
+		Labels:    map[string]string{"team": "shop", "tier": "front"},
+	}
+
+	tests := []struct {
+		name      string
+		scope     domain.ContainerScope
+		container *domain.ContainerDetails
+		want      bool
+	}{
+		{"unscoped matches any container", domain.ContainerScope{}, web, true},
+		{"unscoped matches no container", domain.ContainerScope{}, nil, true},
+		{"name pattern", domain.ContainerScope{Names: []string{"db*", "web-*"}}, web, true},
+		{"other name pattern", domain.ContainerScope{Names: []string{"db*"}}, web, false},
+		{"labels", domain.ContainerScope{Labels: map[string]string{"team": "shop"}}, web, true},
+		{"all labels are required", domain.ContainerScope{Labels: map[string]string{"team": "shop", "tier": "back"}}, web, false},
+		{"name and labels", domain.ContainerScope{Names: []string{"web-*"}, Labels: map[string]string{"tier": "front"}}, web, true},
+		{"name without labels", domain.ContainerScope{Names: []string{"web-*"}, Labels: map[string]string{"tier": "back"}}, web, false},
+		{"scoped matches no unknown container", domain.ContainerScope{Names: []string{"*"}}, nil, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := scopeMatches(test.scope, test.container); got != test.want {
+				t.Errorf("scopeMatches = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestAccessPolicy(t *testing.T) {
+	policy, err := NewAccessPolicy([]domain.RoleBinding{
+		{Principals: []string{"admin"}, Role: domain.AdminRole},
+		{Principals: []string{"ops-*"}, Role: domain.OperatorRole},
+		{Principals: []string{"shop"}, Role: domain.OperatorRole, Containers: domain.ContainerScope{Names: []string{"web-*"}}},
+		{Principals: []string{"agent-*"}, Role: domain.ViewerRole},
+		{Principals: []string{"agent-*"}, Role: domain.AgentRole},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	web := &domain.ContainerDetails{Container: domain.Container{Names: []string{"web-1"}}}
+	db := &domain.ContainerDetails{Container: domain.Container{Names: []string{"db"}}}
+
+	tests := []struct {
+		principal string
+		required  string
+		container *domain.ContainerDetails
+		global    bool
+		want      bool
+	}{
+		{"admin", domain.AdminRole, nil, true, true},
+		{"admin", domain.AgentRole, nil, true, true},
+		{"ops-1", domain.OperatorRole, db, false, true},
+		{"ops-1", domain.AdminRole, nil, true, false},
+		{"ops-1", domain.AgentRole, nil, true, true},
+		{"shop", domain.OperatorRole, web, false, true},
+		{"shop", domain.OperatorRole, db, false, false},
+		{"shop", domain.OperatorRole, nil, false, false},
+		{"shop", domain.ViewerRole, nil, true, false},
+		{"agent-1", domain.AgentRole, nil, true, true},
+		{"agent-1", domain.ViewerRole, nil, true, true},
+		{"agent-1", domain.OperatorRole, web, false, false},
+		{"stranger", domain.ViewerRole, nil, true, false},
+	}
+
+	for _, test := range tests {
+		principal := domain.Principal{Name: test.principal}
+
+		var got bool
+		if test.global {
+			got = policy.Allows(principal, test.required)
+		} else {
+			got = policy.AllowsContainer(principal, test.required, test.container)
+		}
+		if got != test.want {
+			t.Errorf("%s requiring %s (global %v): allowed = %v, want %v", test.principal, test.required, test.global, got, test.want)
+		}
+	}
+
+	if _, err := NewAccessPolicy([]domain.RoleBinding{{Principals: []string{"x"}, Role: "root"}}); err == nil {
+		t.Error("an unknown role is accepted")
+	}
+}
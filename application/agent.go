@@ -0,0 +1,75 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"godtop/domain"
+	"log"
+	"net/http"
+	"time"
+)
+
+//Agent collects snapshots of the local host and its engines and pushes them to the fleet server
+type Agent struct {
+	Collector *MetricsCollector
+	Sender    domain.WebhookSender
+	URL       string
+	Node      string
+	Headers   map[string]string
+
+	failing bool
+}
+
+//Run collects and reports a snapshot every collector interval until the context is done
+func (a *Agent) Run(ctx context.Context) {
+	ticker := time.NewTicker(a.Collector.Interval)
+	defer ticker.Stop()
+
+	for {
+		a.Collector.collect(ctx)
+		a.report(ctx, a.Collector.Latest())
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+//report pushes the snapshot logging only changes between failing and succeeding reports
+func (a *Agent) report(ctx context.Context, snapshot *domain.MetricsSnapshot) {
+	if snapshot == nil {
+		return
+	}
+
+	err := a.send(ctx, domain.NodeReport{Node: a.Node, Snapshot: *snapshot})
+	switch {
+	case err != nil && !a.failing:
+		log.Printf("agent: cannot report to %s: %s", a.URL, err)
+	case err == nil && a.failing:
+		log.Printf("agent: reporting to %s again", a.URL)
+	}
+	a.failing = err != nil
+}
+
+func (a *Agent) send(ctx context.Context, report domain.NodeReport) error {
+	body, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, a.Collector.Interval)
+	defer cancel()
+
+	status, err := a.Sender.Post(ctx, a.URL, body, a.Headers)
+	if err != nil {
+		return err
+	}
+	if status < 200 || status >= 300 {
+		return fmt.Errorf("server responded %d %s", status, http.StatusText(status))
+	}
+
+	return nil
+}
@@ -0,0 +1,364 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"godtop/domain"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	containerTarget = "container"
+	hostTarget      = "host"
+
+	maxResolvedAlerts = 100
+)
+
+var ruleExpr = regexp.MustCompile(`^\s*(container|host)\s+(\w+)\s*(?:/\s*(\w+))?\s*(>=|<=|==|!=|>|<)\s*"?([^"]*?)"?\s*$`)
+
+//alertCondition is a parsed rule expression
+type alertCondition struct {
+	target      string
+	numerator   string
+	denominator string
+	operator    string
+	threshold   string
+}
+
+type alertRule struct {
+	domain.AlertRule
+	condition alertCondition
+}
+
+//parseAlertRule validates a rule and its expression
+func parseAlertRule(rule domain.AlertRule) (alertRule, error) {
+	if rule.Name == "" {
+		return alertRule{}, fmt.Errorf("alert rule %q has no name", rule.Expr)
+	}
+
+	match := ruleExpr.FindStringSubmatch(rule.Expr)
+	if match == nil {
+		return alertRule{}, fmt.Errorf("alert rule %s: cannot parse expression %q", rule.Name, rule.Expr)
+	}
+
+	condition := alertCondition{
+		target:      match[1],
+		numerator:   strings.ToLower(match[2]),
+		denominator: strings.ToLower(match[3]),
+		operator:    match[4],
+		threshold:   match[5],
+	}
+
+	known := lowerKeys(HostInfoValues(&domain.HostInfo{}))
+	if condition.target == containerTarget {
+		known = lowerKeys(ContainerStatsValues(&domain.ContainerStats{}))
+	}
+	for _, name := range []string{condition.numerator, condition.denominator} {
+		if _, ok := known[name]; name != "" && !ok && !condition.isText() {
+			return alertRule{}, fmt.Errorf("alert rule %s: unknown %s metric %q", rule.Name, condition.target, name)
+		}
+	}
+
+	if _, err := strconv.ParseFloat(condition.threshold, 64); err != nil && !condition.isText() {
+		return alertRule{}, fmt.Errorf("alert rule %s: threshold %q is not a number", rule.Name, condition.threshold)
+	}
+	if condition.isText() && condition.operator != "==" && condition.operator != "!=" {
+		return alertRule{}, fmt.Errorf("alert rule %s: %s can only be compared with == or !=", rule.Name, condition.numerator)
+	}
+	if rule.Containers != "" {
+		if _, err := path.Match(rule.Containers, ""); err != nil {
+			return alertRule{}, fmt.Errorf("alert rule %s: invalid containers pattern: %w", rule.Name, err)
+		}
+	}
+
+	return alertRule{AlertRule: rule, condition: condition}, nil
+}
+
+//isText reports whether the condition compares a text field of a container
+func (c alertCondition) isText() bool {
+	return c.target == containerTarget && c.denominator == "" && (c.numerator == "state" || c.numerator == "status")
+}
+
+//evaluate returns whether the condition holds and the compared value,
+//ok is false when the values are not available
+func (c alertCondition) evaluate(text map[string]string, values map[string]float64) (holds bool, value string, ok bool) {
+	if c.isText() {
+		value = text[c.numerator]
+		if c.operator == "==" {
+			return value == c.threshold, value, true
+		}
+		return value != c.threshold, value, true
+	}
+
+	number, ok := values[c.numerator]
+	if !ok {
+		return false, "", false
+	}
+	if c.denominator != "" {
+		denominator, ok := values[c.denominator]
+		if !ok || denominator == 0 {
+			return false, "", false
+		}
+		number /= denominator
+	}
+
+	threshold, _ := strconv.ParseFloat(c.threshold, 64)
+	value = strconv.FormatFloat(number, 'g', 6, 64)
+
+	switch c.operator {
+	case ">":
+		return number > threshold, value, true
+	case ">=":
+		return number >= threshold, value, true
+	case "<":
+		return number < threshold, value, true
+	case "<=":
+		return number <= threshold, value, true
+	case "==":
+		return number == threshold, value, true
+	default:
+		return number != threshold, value, true
+	}
+}
+
+//AlertEngine evaluates alert rules against snapshots of the collector,
+//Notify is called when an alert fires or resolves
+type AlertEngine struct {
+	Collector *MetricsCollector
+	Interval  time.Duration
+	Notify    func(domain.Alert)
+
+	rules []alertRule
+
+	mu        sync.RWMutex
+	active    map[string]*domain.Alert
+	resolved  []domain.Alert
+	evaluated time.Time
+}
+
+func NewAlertEngine(collector *MetricsCollector, interval time.Duration, rules []domain.AlertRule) (*AlertEngine, error) {
+	engine := &AlertEngine{
+		Collector: collector,
+		Interval:  interval,
+		active:    make(map[string]*domain.Alert),
+	}
+
+	for _, rule := range rules {
+		parsed, err := parseAlertRule(rule)
+		if err != nil {
+			return nil, err
+		}
+		engine.rules = append(engine.rules, parsed)
+	}
+
+	return engine, nil
+}
+
+//Run evaluates the rules against every new snapshot until the context is done
+func (e *AlertEngine) Run(ctx context.Context) {
+	if len(e.rules) == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(e.Interval)
+	defer ticker.Stop()
+
+	for {
+		if snapshot := e.Collector.Latest(); snapshot != nil && snapshot.Time.After(e.evaluated) {
+			e.Evaluate(snapshot)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+//Rules returns configured alert rules
+func (e *AlertEngine) Rules() []domain.AlertRule {
+	result := make([]domain.AlertRule, len(e.rules))
+	for i, rule := range e.rules {
+		result[i] = rule.AlertRule
+	}
+
+	return result
+}
+
+//Alerts returns pending and firing alerts followed by recently resolved ones,
+//an empty state returns alerts of every state
+func (e *AlertEngine) Alerts(state string) []domain.Alert {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	result := []domain.Alert{}
+	for _, alert := range e.active {
+		if state == "" || alert.State == state {
+			result = append(result, *alert)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ActiveSince.Before(result[j].ActiveSince)
+	})
+
+	if state == "" || state == domain.AlertResolved {
+		for i := len(e.resolved) - 1; i >= 0; i-- {
+			result = append(result, e.resolved[i])
+		}
+	}
+
+	return result
+}
+
+//Evaluate updates states of alerts according to the snapshot
+func (e *AlertEngine) Evaluate(snapshot *domain.MetricsSnapshot) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.evaluated = snapshot.Time
+	seen := make(map[string]bool)
+
+	for _, rule := range e.rules {
+		if rule.condition.target == hostTarget {
+			if snapshot.Host == nil {
+				continue
+			}
+			holds, value, ok := rule.condition.evaluate(nil, lowerKeys(HostInfoValues(snapshot.Host)))
+			e.update(rule, hostTarget, domain.Container{}, holds, ok, value, snapshot.Time, seen)
+			continue
+		}
+
+		//containers of engines which could not be listed are unknown, keep their alerts as they are
+		for key, alert := range e.active {
+			for _, engine := range snapshot.FailedEngines {
+				if alert.Rule == rule.Name && alert.Engine == engine {
+					seen[key] = true
+				}
+			}
+		}
+
+		if snapshot.Containers == nil {
+			//containers are unknown, keep their alerts as they are
+			for key, alert := range e.active {
+				if alert.Rule == rule.Name {
+					seen[key] = true
+				}
+			}
+			continue
+		}
+
+		for _, item := range snapshot.Containers {
+			name := item.Container.ID
+			if len(item.Container.Names) > 0 {
+				name = item.Container.Names[0]
+			}
+			if rule.Containers != "" {
+				if matched, _ := path.Match(rule.Containers, name); !matched {
+					continue
+				}
+			}
+
+			text := map[string]string{
+				"state":  item.Container.State,
+				"status": item.Container.Status,
+			}
+			var values map[string]float64
+			if item.Stats != nil {
+				values = lowerKeys(ContainerStatsValues(item.Stats))
+			}
+
+			holds, value, ok := rule.condition.evaluate(text, values)
+			e.update(rule, name, item.Container, holds, ok, value, snapshot.Time, seen)
+		}
+	}
+
+	for key, alert := range e.active {
+		if !seen[key] {
+			e.resolve(key, alert, snapshot.Time)
+		}
+	}
+}
+
+//update starts, fires or keeps the alert of the subject while the condition holds,
+//when the value is not available, e.g. the sample failed, the alert keeps its state
+func (e *AlertEngine) update(rule alertRule, subject string, container domain.Container, holds bool, ok bool, value string, now time.Time, seen map[string]bool) {
+	key := rule.Name + "/" + subject
+	if container.ID != "" {
+		key = rule.Name + "/" + container.ID
+	}
+	alert, active := e.active[key]
+
+	if !ok {
+		if active {
+			seen[key] = true
+		}
+		return
+	}
+	if !holds {
+		return
+	}
+	seen[key] = true
+
+	if !active {
+		alert = &domain.Alert{
+			Rule:        rule.Name,
+			Expr:        rule.Expr,
+			Severity:    rule.Severity,
+			Subject:     subject,
+			ContainerID: container.ID,
+			Engine:      container.Engine,
+			State:       domain.AlertPending,
+			ActiveSince: now,
+		}
+		e.active[key] = alert
+	}
+
+	alert.Value = value
+	if alert.State == domain.AlertPending && now.Sub(alert.ActiveSince) >= rule.For {
+		firedAt := now
+		alert.State = domain.AlertFiring
+		alert.FiredAt = &firedAt
+		e.notify(*alert)
+	}
+}
+
+//resolve drops a pending alert or moves a firing one to resolved alerts
+func (e *AlertEngine) resolve(key string, alert *domain.Alert, now time.Time) {
+	delete(e.active, key)
+	if alert.State != domain.AlertFiring {
+		return
+	}
+
+	resolvedAt := now
+	alert.State = domain.AlertResolved
+	alert.ResolvedAt = &resolvedAt
+
+	e.notify(*alert)
+
+	e.resolved = append(e.resolved, *alert)
+	if len(e.resolved) > maxResolvedAlerts {
+		e.resolved = e.resolved[len(e.resolved)-maxResolvedAlerts:]
+	}
+}
+
+func (e *AlertEngine) notify(alert domain.Alert) {
+	if e.Notify != nil {
+		e.Notify(alert)
+	}
+}
+
+func lowerKeys(values map[string]float64) map[string]float64 {
+	result := make(map[string]float64, len(values))
+	for name, value := range values {
+		result[strings.ToLower(name)] = value
+	}
+
+	return result
+}
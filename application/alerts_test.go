@@ -0,0 +1,152 @@
+package application
+
+import (
+	"godtop/domain"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseAlertRule(t *testing.T) {
+	tests := []struct {
+		expr       string
+		containers string
+		want       alertCondition
+		err        string
+	}{
+		{expr: "container cpuUsage > 80",
+			want: alertCondition{target: "container", numerator: "cpuusage", operator: ">", threshold: "80"}},
+		{expr: "  host usedMemory/totalMemory>=0.9  ",
+			want: alertCondition{target: "host", numerator: "usedmemory", denominator: "totalmemory", operator: ">=", threshold: "0.9"}},
+		{expr: "container MEMORYUSAGE <= 1e2",
+			want: alertCondition{target: "container", numerator: "memoryusage", operator: "<=", threshold: "1e2"}},
+		{expr: `container state != "running"`,
+			want: alertCondition{target: "container", numerator: "state", operator: "!=", threshold: "running"}},
+		{expr: "container Status == Up", containers: "web-*",
+			want: alertCondition{target: "container", numerator: "status", operator: "==", threshold: "Up"}},
+
+		{expr: "cpuUsage > 80", err: "cannot parse expression"},
+		{expr: "container cpuUsage => 80", err: "cannot parse expression"},
+		{expr: "container cpuUsage >", err: "is not a number"},
+		{expr: "container load > 1", err: `unknown container metric "load"`},
+		{expr: "host pids > 100", err: `unknown host metric "pids"`},
+		{expr: "container usedMemory / load > 1", err: `unknown container metric "load"`},
+		{expr: "container cpuUsage > high", err: "is not a number"},
+		{expr: `container state > "running"`, err: "can only be compared with == or !="},
+		{expr: `host state == "running"`, err: `unknown host metric "state"`},
+		{expr: "container cpuUsage > 80", containers: "web-[", err: "invalid containers pattern"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expr, func(t *testing.T) {
+			rule, err := parseAlertRule(domain.AlertRule{Name: "rule", Expr: test.expr, Containers: test.containers})
+
+			if test.err != "" {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Fatalf("error = %v, want %q", err, test.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if rule.condition != test.want {
+				t.Errorf("condition = %+v, want %+v", rule.condition, test.want)
+			}
+		})
+	}
+
+	if _, err := parseAlertRule(domain.AlertRule{Expr: "host cpuUsage > 1"}); err == nil {
+		t.Error("a rule without a name is accepted")
+	}
+}
+
+func containerSnapshot(at time.Time, name string, state string, stats *domain.ContainerStats) *domain.MetricsSnapshot {
+	return &domain.MetricsSnapshot{
+		Time: at,
+		Containers: []domain.ContainerSnapshot{{
+			Container: domain.Container{ID: name + "-id", Names: []string{name}, State: state, Engine: "local"},
+			Stats:     stats,
+		}},
+	}
+}
+
+func TestAlertEngineTransitions(t *testing.T) {
+	start := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	busy := &domain.ContainerStats{CpuUsage: 95}
+	idle := &domain.ContainerStats{CpuUsage: 5}
+
+	type step struct {
+		snapshot *domain.MetricsSnapshot
+		state    string
+	}
+	tests := []struct {
+		name  string
+		rule  domain.AlertRule
+		steps []step
+	}{
+		{"fires at once without for", domain.AlertRule{Expr: "container cpuUsage > 80"}, []step{
+			{containerSnapshot(start, "web", "running", busy), domain.AlertFiring},
+			{containerSnapshot(start.Add(time.Minute), "web", "running", idle), domain.AlertResolved},
+		}},
+		{"stays pending for the hold-down", domain.AlertRule{Expr: "container cpuUsage > 80", For: 2 * time.Minute}, []step{
+			{containerSnapshot(start, "web", "running", busy), domain.AlertPending},
+			{containerSnapshot(start.Add(time.Minute), "web", "running", busy), domain.AlertPending},
+			{containerSnapshot(start.Add(2*time.Minute), "web", "running", busy), domain.AlertFiring},
+			{containerSnapshot(start.Add(3*time.Minute), "web", "running", idle), domain.AlertResolved},
+		}},
+		{"pending is dropped without resolving", domain.AlertRule{Expr: "container cpuUsage > 80", For: 2 * time.Minute}, []step{
+			{containerSnapshot(start, "web", "running", busy), domain.AlertPending},
+			{containerSnapshot(start.Add(time.Minute), "web", "running", idle), ""},
+		}},
+		{"missing sample keeps the state", domain.AlertRule{Expr: "container cpuUsage > 80"}, []step{
+			{containerSnapshot(start, "web", "running", busy), domain.AlertFiring},
+			{containerSnapshot(start.Add(time.Minute), "web", "running", nil), domain.AlertFiring},
+			{&domain.MetricsSnapshot{Time: start.Add(2 * time.Minute), FailedEngines: []string{"local"}}, domain.AlertFiring},
+			{containerSnapshot(start.Add(3*time.Minute), "web", "running", idle), domain.AlertResolved},
+		}},
+		{"compares state text", domain.AlertRule{Expr: `container state != "running"`}, []step{
+			{containerSnapshot(start, "web", "running", nil), ""},
+			{containerSnapshot(start.Add(time.Minute), "web", "exited", nil), domain.AlertFiring},
+			{containerSnapshot(start.Add(2*time.Minute), "web", "running", nil), domain.AlertResolved},
+		}},
+		{"skips containers outside the pattern", domain.AlertRule{Expr: "container cpuUsage > 80", Containers: "db*"}, []step{
+			{containerSnapshot(start, "web", "running", busy), ""},
+		}},
+		{"matches containers of the pattern", domain.AlertRule{Expr: "container cpuUsage > 80", Containers: "w?b"}, []step{
+			{containerSnapshot(start, "web", "running", busy), domain.AlertFiring},
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.rule.Name = "rule"
+			engine, err := NewAlertEngine(nil, time.Minute, []domain.AlertRule{test.rule})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var notified []string
+			engine.Notify = func(alert domain.Alert) {
+				notified = append(notified, alert.State)
+			}
+
+			for i, step := range test.steps {
+				engine.Evaluate(step.snapshot)
+
+				state := ""
+				if alerts := engine.Alerts(""); len(alerts) > 0 {
+					state = alerts[0].State
+				}
+				if state != step.state {
+					t.Fatalf("step %d: state = %q, want %q", i, state, step.state)
+				}
+			}
+
+			for i := 1; i < len(notified); i++ {
+				if notified[i] == notified[i-1] {
+					t.Errorf("%s is notified twice in a row", notified[i])
+				}
+			}
+		})
+	}
+}
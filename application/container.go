@@ -2,7 +2,20 @@ package application
 
 import (
 	"context"
+	"fmt"
 	"godtop/domain"
+	"sync"
+	"time"
+)
+
+const (
+	RemovedState = "removed"
+
+	//maxParallelStats bounds concurrent stats reads of the metrics collector against an engine
+	maxParallelStats = 8
+	//maxParallelBulkStats bounds concurrent stats reads of a bulk request, which must finish within its deadline
+	//while a read mostly waits the second the daemon takes between two samples
+	maxParallelBulkStats = 32
 )
 
 type ContainerInteractor struct {
@@ -14,6 +27,17 @@ func (i *ContainerInteractor) Get(ctx context.Context, nameOrId string) (*domain
 	return i.Service.GetContainer(ctx, nameOrId)
 }
 
+//Inspect returns detailed information about a container with secrets in its environment masked
+func (i *ContainerInteractor) Inspect(ctx context.Context, nameOrId string) (*domain.ContainerDetails, error) {
+	details, err := i.Service.InspectContainer(ctx, nameOrId)
+	if err != nil {
+		return nil, err
+	}
+
+	details.Env = MaskSecrets(details.Env)
+	return details, nil
+}
+
 //GetAll returns all running containers
 func (i *ContainerInteractor) GetRunning(ctx context.Context) (*[]domain.Container, error) {
 	return i.Service.GetContainers(ctx, false)
@@ -24,7 +48,122 @@ func (i *ContainerInteractor) GetAll(ctx context.Context) (*[]domain.Container,
 	return i.Service.GetContainers(ctx, true)
 }
 
-//GetStats returns real-time statistics of a container
-func (i *ContainerInteractor) GetStats(ctx context.Context, containerId string, stream bool) (*domain.ContainerStats, error) {
-	return i.Service.GetContainerStats(ctx, containerId, stream)
+//GetStats returns a single sample of real-time statistics of a container, StreamStats keeps sampling
+func (i *ContainerInteractor) GetStats(ctx context.Context, containerId string) (*domain.ContainerStats, error) {
+	return i.Service.GetContainerStats(ctx, containerId)
+}
+
+//GetRunningStats samples statistics of all running containers with bounded parallelism,
+//containers which could not be sampled, also because the context is done, are reported in the errors by id
+func (i *ContainerInteractor) GetRunningStats(ctx context.Context) (map[string]*domain.ContainerStats, map[string]error, error) {
+	containers, err := i.GetRunning(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		slots  = make(chan struct{}, maxParallelBulkStats)
+		result = make(map[string]*domain.ContainerStats, len(*containers))
+		errs   = map[string]error{}
+	)
+
+	for _, container := range *containers {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+
+			var stats *domain.ContainerStats
+			var err error
+			select {
+			case slots <- struct{}{}:
+				stats, err = i.Service.GetContainerStats(ctx, id)
+				<-slots
+			case <-ctx.Done():
+				err = domain.NewError(domain.ErrTimeout, fmt.Errorf("not sampled in time: %w", ctx.Err()))
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs[id] = err
+				return
+			}
+			result[id] = stats
+		}(container.ID)
+	}
+	wg.Wait()
+
+	return result, errs, nil
+}
+
+//StreamStats returns statistics of a container until the context is done
+func (i *ContainerInteractor) StreamStats(ctx context.Context, containerId string) (<-chan domain.ContainerStats, error) {
+	return i.Service.StreamContainerStats(ctx, containerId)
+}
+
+//Start starts a container and returns its resulting state
+func (i *ContainerInteractor) Start(ctx context.Context, nameOrId string) (*domain.Container, error) {
+	return i.act(ctx, nameOrId, func() error {
+		return i.Service.StartContainer(ctx, nameOrId)
+	})
+}
+
+//Stop stops a container, killing it after timeout, and returns its resulting state
+func (i *ContainerInteractor) Stop(ctx context.Context, nameOrId string, timeout *time.Duration) (*domain.Container, error) {
+	return i.act(ctx, nameOrId, func() error {
+		return i.Service.StopContainer(ctx, nameOrId, timeout)
+	})
+}
+
+//Restart restarts a container, killing it after timeout, and returns its resulting state
+func (i *ContainerInteractor) Restart(ctx context.Context, nameOrId string, timeout *time.Duration) (*domain.Container, error) {
+	return i.act(ctx, nameOrId, func() error {
+		return i.Service.RestartContainer(ctx, nameOrId, timeout)
+	})
+}
+
+//Pause pauses all processes of a container and returns its resulting state
+func (i *ContainerInteractor) Pause(ctx context.Context, nameOrId string) (*domain.Container, error) {
+	return i.act(ctx, nameOrId, func() error {
+		return i.Service.PauseContainer(ctx, nameOrId)
+	})
+}
+
+//Unpause resumes all processes of a container and returns its resulting state
+func (i *ContainerInteractor) Unpause(ctx context.Context, nameOrId string) (*domain.Container, error) {
+	return i.act(ctx, nameOrId, func() error {
+		return i.Service.UnpauseContainer(ctx, nameOrId)
+	})
+}
+
+//Kill sends a signal to a container and returns its resulting state
+func (i *ContainerInteractor) Kill(ctx context.Context, nameOrId string, signal string) (*domain.Container, error) {
+	return i.act(ctx, nameOrId, func() error {
+		return i.Service.KillContainer(ctx, nameOrId, signal)
+	})
+}
+
+//Remove removes a container and returns its last known state
+func (i *ContainerInteractor) Remove(ctx context.Context, nameOrId string, force bool, removeVolumes bool) (*domain.Container, error) {
+	container, err := i.Service.GetContainer(ctx, nameOrId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := i.Service.RemoveContainer(ctx, container.ID, force, removeVolumes); err != nil {
+		return nil, err
+	}
+
+	container.State = RemovedState
+	return container, nil
+}
+
+func (i *ContainerInteractor) act(ctx context.Context, nameOrId string, action func() error) (*domain.Container, error) {
+	if err := action(); err != nil {
+		return nil, err
+	}
+
+	return i.Service.GetContainer(ctx, nameOrId)
 }
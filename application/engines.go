@@ -0,0 +1,118 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"godtop/domain"
+	"sort"
+	"sync"
+)
+
+//AllEngines selects every engine in aggregated views
+const AllEngines = "*"
+
+//Engines are named docker services, the first added one is used when no engine is selected
+type Engines struct {
+	names    []string
+	services map[string]domain.DockerService
+}
+
+func NewEngines() *Engines {
+	return &Engines{services: make(map[string]domain.DockerService)}
+}
+
+//Add registers the service under a unique name
+func (e *Engines) Add(name string, service domain.DockerService) error {
+	if name == "" || name == AllEngines {
+		return fmt.Errorf("invalid engine name %q", name)
+	}
+	if _, ok := e.services[name]; ok {
+		return fmt.Errorf("engine %s is registered twice", name)
+	}
+
+	e.names = append(e.names, name)
+	e.services[name] = service
+	return nil
+}
+
+//Names returns engine names in the order they were added
+func (e *Engines) Names() []string {
+	return append([]string(nil), e.names...)
+}
+
+//Default returns the service used when no engine is selected
+func (e *Engines) Default() domain.DockerService {
+	return e.services[e.DefaultName()]
+}
+
+//DefaultName returns the name of the engine used when no engine is selected
+func (e *Engines) DefaultName() string {
+	return e.names[0]
+}
+
+//Get returns the service of the engine, the default one when the name is empty
+func (e *Engines) Get(name string) (domain.DockerService, error) {
+	if name == "" {
+		return e.Default(), nil
+	}
+
+	service, ok := e.services[name]
+	if !ok {
+		return nil, domain.NewError(domain.ErrNotFound, fmt.Errorf("unknown engine %q", name))
+	}
+
+	return service, nil
+}
+
+//Status returns connection states of the engines in the order they were added
+func (e *Engines) Status() []domain.EngineStatus {
+	result := make([]domain.EngineStatus, len(e.names))
+	for i, name := range e.names {
+		result[i] = e.services[name].Status()
+	}
+
+	return result
+}
+
+type EnginesInteractor struct {
+	Engines *Engines
+}
+
+//GetContainers lists containers of every engine concurrently in the order of engines,
+//engines which fail are reported in the errors by name and do not fail the whole list
+func (i *EnginesInteractor) GetContainers(ctx context.Context, all bool) ([]domain.Container, map[string]error) {
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		result = []domain.Container{}
+		errs   = map[string]error{}
+	)
+
+	for _, name := range i.Engines.names {
+		wg.Add(1)
+		go func(name string, service domain.DockerService) {
+			defer wg.Done()
+
+			containers, err := service.GetContainers(ctx, all)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs[name] = err
+				return
+			}
+			result = append(result, *containers...)
+		}(name, i.Engines.services[name])
+	}
+	wg.Wait()
+
+	order := make(map[string]int, len(i.Engines.names))
+	for index, name := range i.Engines.names {
+		order[name] = index
+	}
+	sort.SliceStable(result, func(a, b int) bool {
+		return order[result[a].Engine] < order[result[b].Engine]
+	})
+
+	return result, errs
+}
@@ -0,0 +1,215 @@
+package application
+
+import (
+	"context"
+	"godtop/domain"
+	"log"
+	"sync"
+	"time"
+)
+
+const (
+	minWatchBackoff  = time.Second
+	maxWatchBackoff  = 30 * time.Second
+	subscriberBuffer = 16
+)
+
+//EventFilter selects events of the journal, zero fields match everything
+type EventFilter struct {
+	Engine      string
+	ContainerID string
+	Name        string
+	Actions     []string
+	Since       time.Time
+	Until       time.Time
+	Limit       int
+}
+
+//Match reports whether the event passes the filter ignoring Limit
+func (f EventFilter) Match(event domain.ContainerEvent) bool {
+	if f.Engine != "" && event.Engine != f.Engine {
+		return false
+	}
+	if f.ContainerID != "" && event.ContainerID != f.ContainerID {
+		return false
+	}
+	if f.Name != "" && event.Name != f.Name {
+		return false
+	}
+	if !f.Since.IsZero() && event.Time.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && event.Time.After(f.Until) {
+		return false
+	}
+	if len(f.Actions) == 0 {
+		return true
+	}
+
+	for _, action := range f.Actions {
+		if event.Action == action {
+			return true
+		}
+	}
+	return false
+}
+
+//EventJournal keeps a bounded history of container lifecycle events of every engine and shares live events with subscribers
+type EventJournal struct {
+	Engines  *Engines
+	Capacity int
+
+	mu          sync.RWMutex
+	events      []domain.ContainerEvent //ring of Capacity events starting at head
+	head        int
+	size        int
+	subscribers map[chan domain.ContainerEvent]EventFilter
+}
+
+func NewEventJournal(engines *Engines, capacity int) *EventJournal {
+	return &EventJournal{
+		Engines:     engines,
+		Capacity:    capacity,
+		subscribers: make(map[chan domain.ContainerEvent]EventFilter),
+	}
+}
+
+//Run watches docker events of every engine until the context is done
+func (j *EventJournal) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, name := range j.Engines.Names() {
+		service, _ := j.Engines.Get(name)
+
+		wg.Add(1)
+		go func(name string, service domain.DockerService) {
+			defer wg.Done()
+			j.watch(ctx, name, service)
+		}(name, service)
+	}
+	wg.Wait()
+}
+
+//watch records events of the engine, reconnecting with backoff when the stream fails,
+//a reconnect replays events since the watch started or since the last recorded event
+func (j *EventJournal) watch(ctx context.Context, engine string, service domain.DockerService) {
+	backoff := minWatchBackoff
+	since := time.Now()
+
+	for {
+		events, errs := service.WatchEvents(ctx, since)
+		for event := range events {
+			event.Engine = engine
+			j.Record(event)
+			since = event.Time.Add(time.Nanosecond)
+			backoff = minWatchBackoff
+		}
+
+		if err := <-errs; err != nil && ctx.Err() == nil {
+			log.Printf("events: watch of engine %s failed, retrying in %s: %s", engine, backoff, err)
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return
+		}
+
+		if backoff *= 2; backoff > maxWatchBackoff {
+			backoff = maxWatchBackoff
+		}
+	}
+}
+
+//Record appends an event to the journal dropping the oldest one when full
+func (j *EventJournal) Record(event domain.ContainerEvent) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.events == nil && j.Capacity > 0 {
+		j.events = make([]domain.ContainerEvent, j.Capacity)
+	}
+	if len(j.events) > 0 {
+		j.events[(j.head+j.size)%len(j.events)] = event
+		if j.size < len(j.events) {
+			j.size++
+		} else {
+			j.head = (j.head + 1) % len(j.events)
+		}
+	}
+
+	for subscriber, filter := range j.subscribers {
+		if !filter.Match(event) {
+			continue
+		}
+
+		select {
+		case subscriber <- event:
+		default:
+			//slow subscribers miss events instead of blocking the journal
+		}
+	}
+}
+
+//Query returns recorded events matching the filter in time order,
+//with a limit only the latest events are returned
+func (j *EventJournal) Query(filter EventFilter) []domain.ContainerEvent {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	result := []domain.ContainerEvent{}
+	for i := 0; i < j.size; i++ {
+		if event := j.events[(j.head+i)%len(j.events)]; filter.Match(event) {
+			result = append(result, event)
+		}
+	}
+
+	if filter.Limit > 0 && len(result) > filter.Limit {
+		result = result[len(result)-filter.Limit:]
+	}
+
+	return result
+}
+
+//Subscribe returns a channel receiving live events matching the filter,
+//the channel is closed when the context is done
+func (j *EventJournal) Subscribe(ctx context.Context, filter EventFilter) <-chan domain.ContainerEvent {
+	subscriber := make(chan domain.ContainerEvent, subscriberBuffer)
+
+	j.mu.Lock()
+	j.subscribers[subscriber] = filter
+	j.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+
+		j.mu.Lock()
+		delete(j.subscribers, subscriber)
+		close(subscriber)
+		j.mu.Unlock()
+	}()
+
+	return subscriber
+}
+
+//Timeline returns recorded events of a container of the engine by id or name, the default engine when it is empty,
+//containers which no longer exist are matched by the id or name itself
+func (j *EventJournal) Timeline(ctx context.Context, engine string, nameOrId string) ([]domain.ContainerEvent, error) {
+	service, err := j.Engines.Get(engine)
+	if err != nil {
+		return nil, err
+	}
+	if engine == "" {
+		engine = j.Engines.DefaultName()
+	}
+
+	if container, err := service.GetContainer(ctx, nameOrId); err == nil {
+		return j.Query(EventFilter{Engine: engine, ContainerID: container.ID}), nil
+	}
+
+	byId := j.Query(EventFilter{Engine: engine, ContainerID: nameOrId})
+	if len(byId) > 0 {
+		return byId, nil
+	}
+
+	return j.Query(EventFilter{Engine: engine, Name: nameOrId}), nil
+}
@@ -0,0 +1,195 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"godtop/domain"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+//forgetNodeAfter drops nodes which have not reported for a day
+const forgetNodeAfter = 24 * time.Hour
+
+//Fleet is the registry of nodes reporting to the server with their latest snapshots,
+//Local is the node name of the server itself which agents cannot report under
+type Fleet struct {
+	Local      string
+	StaleAfter time.Duration
+
+	mu    sync.RWMutex
+	nodes map[string]*fleetNode
+}
+
+//fleetNode is bound to the principal of its first report until it is forgotten
+type fleetNode struct {
+	node     domain.Node
+	snapshot domain.MetricsSnapshot
+	owner    string
+}
+
+func NewFleet(local string, staleAfter time.Duration) *Fleet {
+	return &Fleet{
+		Local:      local,
+		StaleAfter: staleAfter,
+		nodes:      make(map[string]*fleetNode),
+	}
+}
+
+//Ingest registers the node pushed by the principal or replaces its snapshot,
+//reports for the local node or for a node registered by another principal are refused
+func (f *Fleet) Ingest(report domain.NodeReport, address string, principal string) error {
+	if report.Node == "" || strings.ContainsAny(report.Node, "/ ") {
+		return domain.NewError(domain.ErrInvalidArgument, errors.New("node name is required and must not contain slashes or spaces"))
+	}
+
+	return f.register(report, address, principal, false)
+}
+
+func (f *Fleet) register(report domain.NodeReport, address string, principal string, local bool) error {
+	node := domain.Node{
+		Name:     report.Node,
+		Address:  address,
+		Status:   domain.NodeHealthy,
+		LastSeen: time.Now(),
+		Host:     report.Snapshot.Host,
+	}
+	if report.Snapshot.Containers == nil || len(report.Snapshot.FailedEngines) > 0 {
+		node.Status = domain.NodeDegraded
+	}
+	for _, item := range report.Snapshot.Containers {
+		node.Containers++
+		if item.Container.State == runningState {
+			node.Running++
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !local && report.Node == f.Local {
+		return domain.NewError(domain.ErrForbidden, fmt.Errorf("node %s is the server itself", report.Node))
+	}
+	previous, ok := f.nodes[report.Node]
+	if ok && previous.owner != principal {
+		return domain.NewError(domain.ErrForbidden, fmt.Errorf("node %s is reported by another principal", report.Node))
+	}
+	if ok && previous.node.Status == domain.NodeStale {
+		log.Printf("fleet: node %s is reporting again", report.Node)
+	}
+	f.nodes[report.Node] = &fleetNode{node: node, snapshot: report.Snapshot, owner: principal}
+
+	return nil
+}
+
+//Follow ingests snapshots of the local collector under the local node name until the context is done,
+//so the server itself is a part of the fleet
+func (f *Fleet) Follow(ctx context.Context, collector *MetricsCollector) {
+	ticker := time.NewTicker(collector.Interval)
+	defer ticker.Stop()
+
+	var last time.Time
+	for {
+		if snapshot := collector.Latest(); snapshot != nil && snapshot.Time.After(last) {
+			last = snapshot.Time
+			if err := f.register(domain.NodeReport{Node: f.Local, Snapshot: *snapshot}, "local", "", true); err != nil {
+				log.Printf("fleet: cannot register local node: %s", err)
+				return
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+//Run marks nodes stale when they stop reporting and forgets them after a day until the context is done
+func (f *Fleet) Run(ctx context.Context) {
+	ticker := time.NewTicker(f.StaleAfter / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case now := <-ticker.C:
+			f.sweep(now)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (f *Fleet) sweep(now time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for name, item := range f.nodes {
+		silence := now.Sub(item.node.LastSeen)
+		switch {
+		case silence > forgetNodeAfter:
+			log.Printf("fleet: forgetting node %s, last seen %s", name, item.node.LastSeen.Format(time.RFC3339))
+			delete(f.nodes, name)
+		case silence > f.StaleAfter && item.node.Status != domain.NodeStale:
+			log.Printf("fleet: node %s is stale, last seen %s", name, item.node.LastSeen.Format(time.RFC3339))
+			item.node.Status = domain.NodeStale
+		}
+	}
+}
+
+//Nodes returns registered nodes by name
+func (f *Fleet) Nodes() []domain.Node {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	result := make([]domain.Node, 0, len(f.nodes))
+	for _, item := range f.nodes {
+		result = append(result, item.node)
+	}
+	sort.Slice(result, func(a, b int) bool {
+		return result[a].Name < result[b].Name
+	})
+
+	return result
+}
+
+//Node returns a registered node with its latest snapshot
+func (f *Fleet) Node(name string) (*domain.Node, *domain.MetricsSnapshot, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	item, ok := f.nodes[name]
+	if !ok {
+		return nil, nil, domain.NewError(domain.ErrNotFound, errors.New("unknown node "+name))
+	}
+
+	node, snapshot := item.node, item.snapshot
+	return &node, &snapshot, nil
+}
+
+//Containers merges the latest container snapshots of the nodes marking them with the node name,
+//containers of stale nodes are left out unless asked for since their state is outdated
+func (f *Fleet) Containers(includeStale bool) []domain.ContainerSnapshot {
+	result := []domain.ContainerSnapshot{}
+	for _, node := range f.Nodes() {
+		if node.Status == domain.NodeStale && !includeStale {
+			continue
+		}
+
+		_, snapshot, err := f.Node(node.Name)
+		if err != nil {
+			continue
+		}
+		for _, item := range snapshot.Containers {
+			item.Container.Node = node.Name
+			result = append(result, item)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,144 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"godtop/domain"
+	"sync"
+	"time"
+)
+
+//HostIntervals are the intervals the host is sampled at, requested intervals are snapped to one of them
+//so the number of sampling loops stays bounded whatever intervals subscribers ask for
+var HostIntervals = []time.Duration{
+	500 * time.Millisecond, time.Second, 2 * time.Second, 5 * time.Second, 10 * time.Second, 30 * time.Second, time.Minute,
+}
+
+//HostSampler shares periodic host snapshots between all subscribers of the same interval,
+//so the host is sampled once per interval regardless of the number of subscribers
+type HostSampler struct {
+	Service domain.HostService
+
+	mu    sync.Mutex
+	feeds map[time.Duration]*hostFeed
+}
+
+type hostFeed struct {
+	subscribers map[chan domain.HostInfo]struct{}
+	last        *domain.HostInfo
+	cancel      context.CancelFunc
+}
+
+func NewHostSampler(service domain.HostService) *HostSampler {
+	return &HostSampler{
+		Service: service,
+		feeds:   make(map[time.Duration]*hostFeed),
+	}
+}
+
+//Subscribe returns a channel receiving host snapshots every interval snapped to HostIntervals,
+//the channel is closed when the context is done
+func (s *HostSampler) Subscribe(ctx context.Context, interval time.Duration) <-chan domain.HostInfo {
+	interval = SnapHostInterval(interval)
+	subscriber := make(chan domain.HostInfo, 1)
+
+	s.mu.Lock()
+	feed, ok := s.feeds[interval]
+	if !ok {
+		feedCtx, cancel := context.WithCancel(context.Background())
+		feed = &hostFeed{
+			subscribers: make(map[chan domain.HostInfo]struct{}),
+			cancel:      cancel,
+		}
+		s.feeds[interval] = feed
+		go s.run(feedCtx, interval, feed)
+	}
+
+	feed.subscribers[subscriber] = struct{}{}
+	if feed.last != nil {
+		subscriber <- *feed.last
+	}
+	s.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		s.unsubscribe(interval, feed, subscriber)
+	}()
+
+	return subscriber
+}
+
+//SnapHostInterval returns the longest of HostIntervals which is not longer than the interval,
+//so snapshots never come less often than asked, or the shortest one
+func SnapHostInterval(interval time.Duration) time.Duration {
+	result := HostIntervals[0]
+	for _, candidate := range HostIntervals {
+		if candidate <= interval {
+			result = candidate
+		}
+	}
+
+	return result
+}
+
+//Check samples the host once and fails when sampling does not finish in time or returns no memory information
+func (s *HostSampler) Check(ctx context.Context) error {
+	result := make(chan *domain.HostInfo, 1)
+	go func() {
+		result <- s.Service.GetInfo(ctx)
+	}()
+
+	select {
+	case info := <-result:
+		if info == nil || info.TotalMemory == 0 {
+			return errors.New("host information is not available")
+		}
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *HostSampler) unsubscribe(interval time.Duration, feed *hostFeed, subscriber chan domain.HostInfo) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(feed.subscribers, subscriber)
+	close(subscriber)
+
+	if len(feed.subscribers) == 0 {
+		feed.cancel()
+		delete(s.feeds, interval)
+	}
+}
+
+func (s *HostSampler) run(ctx context.Context, interval time.Duration, feed *hostFeed) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		info := s.Service.GetInfo(ctx)
+		s.publish(feed, info)
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (s *HostSampler) publish(feed *hostFeed, info *domain.HostInfo) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	feed.last = info
+	for subscriber := range feed.subscribers {
+		//drop the previous snapshot if the subscriber is too slow to read it
+		select {
+		case <-subscriber:
+		default:
+		}
+		subscriber <- *info
+	}
+}
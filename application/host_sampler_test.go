@@ -0,0 +1,67 @@
+package application
+
+import (
+	"context"
+	"godtop/domain"
+	"testing"
+	"time"
+)
+
+type staticHostService struct{}
+
+func (staticHostService) GetInfo(ctx context.Context) *domain.HostInfo {
+	return &domain.HostInfo{TotalMemory: 1}
+}
+
+func TestSnapHostInterval(t *testing.T) {
+	tests := []struct {
+		interval time.Duration
+		want     time.Duration
+	}{
+		{100 * time.Millisecond, 500 * time.Millisecond},
+		{500 * time.Millisecond, 500 * time.Millisecond},
+		{501 * time.Millisecond, 500 * time.Millisecond},
+		{999 * time.Millisecond, 500 * time.Millisecond},
+		{time.Second, time.Second},
+		{3 * time.Second, 2 * time.Second},
+		{45 * time.Second, 30 * time.Second},
+		{time.Hour, time.Minute},
+	}
+
+	for _, test := range tests {
+		if got := SnapHostInterval(test.interval); got != test.want {
+			t.Errorf("SnapHostInterval(%s) = %s, want %s", test.interval, got, test.want)
+		}
+	}
+}
+
+func TestHostSamplerSharesSnappedFeeds(t *testing.T) {
+	sampler := NewHostSampler(staticHostService{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var subscribers []<-chan domain.HostInfo
+	for interval := 500 * time.Millisecond; interval < 600*time.Millisecond; interval += time.Millisecond {
+		subscribers = append(subscribers, sampler.Subscribe(ctx, interval))
+	}
+	subscribers = append(subscribers, sampler.Subscribe(ctx, 5*time.Second))
+
+	sampler.mu.Lock()
+	feeds := len(sampler.feeds)
+	sampler.mu.Unlock()
+	if feeds != 2 {
+		t.Errorf("%d feeds are sampled, want 2", feeds)
+	}
+
+	cancel()
+	for _, subscriber := range subscribers {
+		for range subscriber {
+		}
+	}
+
+	sampler.mu.Lock()
+	feeds = len(sampler.feeds)
+	sampler.mu.Unlock()
+	if feeds != 0 {
+		t.Errorf("%d feeds are sampled after all subscribers left, want 0", feeds)
+	}
+}
@@ -0,0 +1,16 @@
+package application
+
+import (
+	"context"
+	"godtop/domain"
+)
+
+type LogInteractor struct {
+	Service domain.DockerService
+}
+
+//Get returns demultiplexed log lines of a container,
+//the channel is closed when the logs end or the context is done
+func (i *LogInteractor) Get(ctx context.Context, nameOrId string, options domain.LogOptions) (<-chan domain.LogEntry, error) {
+	return i.Service.GetContainerLogs(ctx, nameOrId, options)
+}
@@ -0,0 +1,107 @@
+package application
+
+import (
+	"context"
+	"godtop/domain"
+	"time"
+)
+
+const HostSeries = "host"
+
+//ContainerSeries returns name of the metrics series of a container
+func ContainerSeries(containerId string) string {
+	return "container/" + containerId
+}
+
+type MetricsInteractor struct {
+	Store   domain.MetricsStore
+	Service domain.DockerService
+}
+
+//GetContainerHistory returns sampled statistics of a container downsampled to step
+func (i *MetricsInteractor) GetContainerHistory(ctx context.Context, nameOrId string, from time.Time, to time.Time, step time.Duration) ([]domain.MetricsBucket, error) {
+	containerId := nameOrId
+	if container, err := i.Service.GetContainer(ctx, nameOrId); err == nil {
+		containerId = container.ID
+	}
+
+	return i.getHistory(ctx, ContainerSeries(containerId), from, to, step)
+}
+
+//GetHostHistory returns sampled host information downsampled to step
+func (i *MetricsInteractor) GetHostHistory(ctx context.Context, from time.Time, to time.Time, step time.Duration) ([]domain.MetricsBucket, error) {
+	return i.getHistory(ctx, HostSeries, from, to, step)
+}
+
+func (i *MetricsInteractor) getHistory(ctx context.Context, series string, from time.Time, to time.Time, step time.Duration) ([]domain.MetricsBucket, error) {
+	buckets, err := i.Store.Range(ctx, series, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return Downsample(buckets, from, step), nil
+}
+
+//NewSample returns a bucket holding a single sample
+func NewSample(t time.Time, values map[string]float64) domain.MetricsBucket {
+	return domain.MetricsBucket{
+		Time:  t,
+		Count: 1,
+		Avg:   values,
+		Min:   values,
+		Max:   values,
+	}
+}
+
+//ContainerStatsValues returns statistics of a container as named values
+func ContainerStatsValues(stats *domain.ContainerStats) map[string]float64 {
+	return map[string]float64{
+		"cpuUsage":        float64(stats.CpuUsage),
+		"cpuUserUsage":    float64(stats.Cpu.User),
+		"cpuKernelUsage":  float64(stats.Cpu.Kernel),
+		"cpuQuotaUsage":   float64(stats.Cpu.QuotaUsage),
+		"usedMemory":      float64(stats.UsedMemory),
+		"memoryUsage":     float64(stats.MemoryUsage),
+		"rssMemory":       float64(stats.Memory.RSS),
+		"cacheMemory":     float64(stats.Memory.Cache),
+		"swapMemory":      float64(stats.Memory.Swap),
+		"rxBytes":         float64(stats.RxBytes),
+		"txBytes":         float64(stats.TxBytes),
+		"blockReadBytes":  float64(stats.BlockIO.ReadBytes),
+		"blockWriteBytes": float64(stats.BlockIO.WriteBytes),
+		"pids":            float64(stats.Pids.Current),
+	}
+}
+
+//HostInfoValues returns host information as named values
+func HostInfoValues(info *domain.HostInfo) map[string]float64 {
+	return map[string]float64{
+		"cpuUsage":        info.CpuUsage,
+		"usedSwapMemory":  float64(info.UsedSwapMemory),
+		"totalSwapMemory": float64(info.TotalSwapMemory),
+		"usedMemory":      float64(info.UsedMemory),
+		"totalMemory":     float64(info.TotalMemory),
+		"usedStorage":     float64(info.UsedStorage),
+		"totalStorage":    float64(info.TotalStorage),
+	}
+}
+
+//Downsample merges time ordered buckets into buckets of step aligned to from,
+//the buckets are returned unchanged when step is not positive
+func Downsample(buckets []domain.MetricsBucket, from time.Time, step time.Duration) []domain.MetricsBucket {
+	if step <= 0 || len(buckets) == 0 {
+		return buckets
+	}
+
+	var result []domain.MetricsBucket
+	for _, bucket := range buckets {
+		start := from.Add(bucket.Time.Sub(from) / step * step)
+		if len(result) == 0 || !result[len(result)-1].Time.Equal(start) {
+			result = append(result, domain.MetricsBucket{Time: start})
+		}
+
+		result[len(result)-1].Merge(bucket)
+	}
+
+	return result
+}
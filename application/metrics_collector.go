@@ -0,0 +1,121 @@
+package application
+
+import (
+	"context"
+	"godtop/domain"
+	"log"
+	"sync"
+	"time"
+)
+
+const runningState = "running"
+
+//MetricsCollector periodically samples statistics of running containers of every engine and the host into a store,
+//without a store only the latest snapshot is kept
+type MetricsCollector struct {
+	Engines     *Engines
+	HostService domain.HostService
+	Store       domain.MetricsStore
+	Interval    time.Duration
+
+	mu     sync.RWMutex
+	latest *domain.MetricsSnapshot
+}
+
+//Run samples metrics every interval until the context is done
+func (c *MetricsCollector) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.Interval)
+	defer ticker.Stop()
+
+	for {
+		c.collect(ctx)
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+//Latest returns the last collected snapshot or nil before the first collection
+func (c *MetricsCollector) Latest() *domain.MetricsSnapshot {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.latest
+}
+
+func (c *MetricsCollector) collect(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, c.Interval)
+	defer cancel()
+
+	snapshot := domain.MetricsSnapshot{Time: time.Now()}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		snapshot.Host = c.HostService.GetInfo(ctx)
+		c.append(ctx, HostSeries, NewSample(snapshot.Time, HostInfoValues(snapshot.Host)))
+	}()
+
+	interactor := EnginesInteractor{Engines: c.Engines}
+	containers, errs := interactor.GetContainers(ctx, true)
+	for _, name := range c.Engines.Names() {
+		if err, ok := errs[name]; ok {
+			log.Printf("collector: cannot list containers of engine %s: %s", name, err)
+			snapshot.FailedEngines = append(snapshot.FailedEngines, name)
+		}
+	}
+	if len(errs) < len(c.Engines.Names()) {
+		snapshot.Containers = make([]domain.ContainerSnapshot, len(containers))
+	}
+
+	slots := make(chan struct{}, maxParallelStats)
+	for i := range snapshot.Containers {
+		container := containers[i]
+		snapshot.Containers[i].Container = container
+		if container.State != runningState {
+			continue
+		}
+
+		wg.Add(1)
+		go func(item *domain.ContainerSnapshot) {
+			defer wg.Done()
+
+			select {
+			case slots <- struct{}{}:
+				defer func() { <-slots }()
+			case <-ctx.Done():
+				return
+			}
+
+			service, err := c.Engines.Get(item.Container.Engine)
+			if err == nil {
+				item.Stats, err = service.GetContainerStats(ctx, item.Container.ID)
+			}
+			if err != nil {
+				log.Printf("collector: cannot get stats of %s: %s", item.Container.ID, err)
+				return
+			}
+			c.append(ctx, ContainerSeries(item.Container.ID), NewSample(snapshot.Time, ContainerStatsValues(item.Stats)))
+		}(&snapshot.Containers[i])
+	}
+
+	wg.Wait()
+
+	c.mu.Lock()
+	c.latest = &snapshot
+	c.mu.Unlock()
+}
+
+func (c *MetricsCollector) append(ctx context.Context, series string, bucket domain.MetricsBucket) {
+	if c.Store == nil {
+		return
+	}
+
+	if err := c.Store.Append(ctx, series, bucket); err != nil {
+		log.Printf("collector: cannot store %s sample: %s", series, err)
+	}
+}
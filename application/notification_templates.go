@@ -0,0 +1,146 @@
+package application
+
+import (
+	"encoding/json"
+	"fmt"
+	"godtop/domain"
+	"strings"
+	"time"
+)
+
+const (
+	firingColor   = "D63232"
+	resolvedColor = "2EB67D"
+	eventColor    = "F2C744"
+)
+
+var bodyRenderers = map[string]func(domain.Notification) interface{}{
+	domain.GenericFormat: genericBody,
+	domain.SlackFormat:   slackBody,
+	domain.TeamsFormat:   teamsBody,
+}
+
+//renderBody returns JSON body of the notification in the receiver format, generic by default
+func renderBody(format string, notification domain.Notification) ([]byte, error) {
+	render, ok := bodyRenderers[format]
+	if !ok {
+		render = genericBody
+	}
+
+	return json.Marshal(render(notification))
+}
+
+func genericBody(notification domain.Notification) interface{} {
+	return notification
+}
+
+//slackBody is accepted by Slack and Mattermost incoming webhooks
+func slackBody(notification domain.Notification) interface{} {
+	type attachment struct {
+		Color string `json:"color"`
+		Title string `json:"title"`
+		Text  string `json:"text"`
+		Ts    int64  `json:"ts"`
+	}
+	type body struct {
+		Text        string       `json:"text"`
+		Attachments []attachment `json:"attachments"`
+	}
+
+	return body{
+		Text: notification.Title,
+		Attachments: []attachment{{
+			Color: "#" + notificationColor(notification),
+			Title: notification.Title,
+			Text:  notification.Text,
+			Ts:    notification.Time.Unix(),
+		}},
+	}
+}
+
+//teamsBody is a legacy actionable message card accepted by Microsoft Teams incoming webhooks
+func teamsBody(notification domain.Notification) interface{} {
+	type body struct {
+		Type       string `json:"@type"`
+		Context    string `json:"@context"`
+		ThemeColor string `json:"themeColor"`
+		Summary    string `json:"summary"`
+		Title      string `json:"title"`
+		Text       string `json:"text"`
+	}
+
+	return body{
+		Type:       "MessageCard",
+		Context:    "https://schema.org/extensions",
+		ThemeColor: notificationColor(notification),
+		Summary:    notification.Title,
+		Title:      notification.Title,
+		Text:       notification.Text,
+	}
+}
+
+func notificationColor(notification domain.Notification) string {
+	switch {
+	case notification.Alert != nil && notification.Alert.State == domain.AlertResolved:
+		return resolvedColor
+	case notification.Alert != nil:
+		return firingColor
+	default:
+		return eventColor
+	}
+}
+
+func alertNotification(alert domain.Alert) domain.Notification {
+	title := fmt.Sprintf("[%s] %s on %s", strings.ToUpper(alert.State), alert.Rule, alert.Subject)
+
+	lines := []string{
+		fmt.Sprintf("%s (value %s)", alert.Expr, alert.Value),
+		"Active since " + alert.ActiveSince.Format(time.RFC3339),
+	}
+	if alert.Severity != "" {
+		lines = append(lines, "Severity: "+alert.Severity)
+	}
+
+	at := time.Now()
+	if alert.ResolvedAt != nil {
+		at = *alert.ResolvedAt
+	} else if alert.FiredAt != nil {
+		at = *alert.FiredAt
+	}
+
+	return domain.Notification{
+		Time:  at,
+		Title: title,
+		Text:  strings.Join(lines, "\n"),
+		Alert: &alert,
+	}
+}
+
+func eventNotification(event domain.ContainerEvent) domain.Notification {
+	name := event.Name
+	if name == "" {
+		name = event.ContainerID
+	}
+
+	var title string
+	switch event.Action {
+	case "die":
+		title = fmt.Sprintf("Container %s died", name)
+		if event.ExitCode != nil {
+			title += fmt.Sprintf(" with exit code %d", *event.ExitCode)
+		}
+	case "oom":
+		title = fmt.Sprintf("Container %s was killed by the OOM killer", name)
+	case "health_status":
+		title = fmt.Sprintf("Container %s is %s", name, event.Health)
+	default:
+		title = fmt.Sprintf("Container %s: %s", name, event.Action)
+	}
+
+	return domain.Notification{
+		Time:  event.Time,
+		Title: title,
+		Text:  fmt.Sprintf("Image %s, id %s", event.Image, event.ContainerID),
+		Event: &event,
+	}
+}
@@ -0,0 +1,223 @@
+package application
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"godtop/domain"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	SignatureHeader = "X-Godtop-Signature"
+
+	maxDeliveries          = 500
+	maxParallelDeliveries  = 8
+	maxQueuedDeliveries    = 100
+	deliveryAttemptTimeout = 10 * time.Second
+)
+
+var defaultReceiverEvents = []string{"die", "oom"}
+
+//Notifier delivers alerts and container events to webhook receivers with retries,
+//deliveries are queued for Run and dropped when the queue is full
+type Notifier struct {
+	Sender    domain.WebhookSender
+	Receivers []domain.Receiver
+	Retries   int
+	Backoff   time.Duration
+
+	queue      chan queuedDelivery
+	mu         sync.RWMutex
+	deliveries []domain.Delivery
+}
+
+func NewNotifier(sender domain.WebhookSender, receivers []domain.Receiver, retries int, backoff time.Duration) (*Notifier, error) {
+	for _, receiver := range receivers {
+		if receiver.Name == "" || receiver.URL == "" {
+			return nil, fmt.Errorf("receiver %q must have a name and an url", receiver.Name)
+		}
+		if _, ok := bodyRenderers[receiver.Format]; !ok && receiver.Format != "" {
+			return nil, fmt.Errorf("receiver %s: unknown format %q", receiver.Name, receiver.Format)
+		}
+	}
+
+	return &Notifier{
+		Sender:    sender,
+		Receivers: receivers,
+		Retries:   retries,
+		Backoff:   backoff,
+		queue:     make(chan queuedDelivery, maxQueuedDeliveries),
+	}, nil
+}
+
+type queuedDelivery struct {
+	receiver     domain.Receiver
+	notification domain.Notification
+}
+
+//Run delivers queued notifications and notifies about journal events receivers are interested in
+//until the context is done, deliveries in progress are cancelled with the context
+func (n *Notifier) Run(ctx context.Context, journal *EventJournal) {
+	if len(n.Receivers) == 0 {
+		return
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < maxParallelDeliveries; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case queued := <-n.queue:
+					n.deliver(ctx, queued.receiver, queued.notification)
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	for event := range journal.Subscribe(ctx, EventFilter{}) {
+		event := event
+		n.notify(eventNotification(event), func(receiver domain.Receiver) bool {
+			return receiverWantsEvent(receiver, event.Action)
+		})
+	}
+
+	wg.Wait()
+}
+
+//NotifyAlert queues a firing or resolved alert for receivers which do not ignore alerts
+func (n *Notifier) NotifyAlert(alert domain.Alert) {
+	n.notify(alertNotification(alert), func(receiver domain.Receiver) bool {
+		return !receiver.IgnoreAlerts
+	})
+}
+
+//Deliveries returns the latest delivery attempts, newest first
+func (n *Notifier) Deliveries() []domain.Delivery {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	result := make([]domain.Delivery, len(n.deliveries))
+	for i, delivery := range n.deliveries {
+		result[len(n.deliveries)-1-i] = delivery
+	}
+
+	return result
+}
+
+func (n *Notifier) notify(notification domain.Notification, wants func(domain.Receiver) bool) {
+	for _, receiver := range n.Receivers {
+		if !wants(receiver) {
+			continue
+		}
+
+		select {
+		case n.queue <- queuedDelivery{receiver: receiver, notification: notification}:
+		default:
+			n.record(domain.Delivery{
+				Time:     time.Now(),
+				Receiver: receiver.Name,
+				Title:    notification.Title,
+				Status:   domain.DeliveryFailed,
+				Error:    "dropped, the delivery queue is full",
+			})
+		}
+	}
+}
+
+//deliver posts the notification retrying with exponential backoff on network errors and 5xx or 429 responses
+func (n *Notifier) deliver(ctx context.Context, receiver domain.Receiver, notification domain.Notification) {
+	delivery := domain.Delivery{
+		Receiver: receiver.Name,
+		Title:    notification.Title,
+	}
+
+	body, err := renderBody(receiver.Format, notification)
+	if err != nil {
+		delivery.Time = time.Now()
+		delivery.Status = domain.DeliveryFailed
+		delivery.Error = err.Error()
+		n.record(delivery)
+		return
+	}
+
+	headers := map[string]string{}
+	if receiver.Secret != "" {
+		headers[SignatureHeader] = Sign(receiver.Secret, body)
+	}
+
+	backoff := n.Backoff
+	for delivery.Attempts = 1; ; delivery.Attempts++ {
+		attemptCtx, cancel := context.WithTimeout(ctx, deliveryAttemptTimeout)
+		delivery.StatusCode, err = n.Sender.Post(attemptCtx, receiver.URL, body, headers)
+		cancel()
+
+		retry := err != nil || delivery.StatusCode >= http.StatusInternalServerError || delivery.StatusCode == http.StatusTooManyRequests
+		if !retry || delivery.Attempts > n.Retries {
+			break
+		}
+
+		select {
+		case <-time.After(backoff):
+			backoff *= 2
+		case <-ctx.Done():
+			err = ctx.Err()
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	delivery.Time = time.Now()
+	delivery.Status = domain.DeliverySucceeded
+	switch {
+	case err != nil:
+		delivery.Status = domain.DeliveryFailed
+		delivery.Error = err.Error()
+	case delivery.StatusCode < 200 || delivery.StatusCode >= 300:
+		delivery.Status = domain.DeliveryFailed
+		delivery.Error = http.StatusText(delivery.StatusCode)
+	}
+
+	n.record(delivery)
+}
+
+func (n *Notifier) record(delivery domain.Delivery) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.deliveries = append(n.deliveries, delivery)
+	if len(n.deliveries) > maxDeliveries {
+		n.deliveries = append(n.deliveries[:0:0], n.deliveries[len(n.deliveries)-maxDeliveries:]...)
+	}
+}
+
+//Sign returns HMAC-SHA256 signature of the body as sha256=<hex>
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func receiverWantsEvent(receiver domain.Receiver, action string) bool {
+	events := receiver.Events
+	if len(events) == 0 {
+		events = defaultReceiverEvents
+	}
+
+	for _, event := range events {
+		if event == action {
+			return true
+		}
+	}
+
+	return false
+}
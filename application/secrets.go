@@ -0,0 +1,42 @@
+package application
+
+import "strings"
+
+const secretMask = "******"
+
+var secretKeyParts = []string{"PASSWORD", "PASSWD", "SECRET", "TOKEN", "KEY", "CREDENTIAL", "PRIVATE", "AUTH"}
+
+//MaskSecrets returns KEY=VALUE environment with values of secret looking keys masked
+func MaskSecrets(env []string) []string {
+	if env == nil {
+		return nil
+	}
+
+	result := make([]string, len(env))
+	for i, variable := range env {
+		result[i] = variable
+
+		index := strings.IndexByte(variable, '=')
+		if index < 0 {
+			continue
+		}
+
+		if IsSecretKey(variable[:index]) {
+			result[i] = variable[:index+1] + secretMask
+		}
+	}
+
+	return result
+}
+
+//IsSecretKey reports whether a key name looks like it holds a secret
+func IsSecretKey(key string) bool {
+	key = strings.ToUpper(key)
+	for _, part := range secretKeyParts {
+		if strings.Contains(key, part) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,361 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"godtop/domain"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	defaultListen          = ":8080"
+	defaultShutdownTimeout = 15 * time.Second
+	defaultShutdownDelay   = 5 * time.Second
+	defaultEngineName      = "local"
+	defaultMetricsInterval = 10 * time.Second
+	defaultHistory         = time.Hour
+	defaultEventsCapacity  = 1000
+	defaultMetricsPath     = "/metrics"
+	defaultAlertsInterval  = 15 * time.Second
+	defaultRetries         = 3
+	defaultBackoff         = time.Second
+	defaultWebhookTimeout  = 10 * time.Second
+	defaultStaleAfter      = 30 * time.Second
+	defaultAgentTimeout    = 10 * time.Second
+	minStaleAfter          = time.Second
+)
+
+//defaultRetention keeps raw samples for 6 hours, minutely rollups for 2 days and hourly rollups for a week
+var defaultRetention = []domain.RetentionTier{
+	{Resolution: 0, Retention: 6 * time.Hour},
+	{Resolution: time.Minute, Retention: 48 * time.Hour},
+	{Resolution: time.Hour, Retention: 7 * 24 * time.Hour},
+}
+
+//Config is the content of the godtop configuration file
+type Config struct {
+	Listen          string              `json:"listen" yaml:"listen"`
+	ShutdownTimeout time.Duration       `json:"shutdownTimeout" yaml:"shutdownTimeout"`
+	ShutdownDelay   time.Duration       `json:"shutdownDelay" yaml:"shutdownDelay"`
+	Debug           bool                `json:"debug" yaml:"debug"`
+	Docker          DockerConfig        `json:"docker" yaml:"docker"`
+	Metrics         MetricsConfig       `json:"metrics" yaml:"metrics"`
+	Events          EventsConfig        `json:"events" yaml:"events"`
+	Exporters       ExportersConfig     `json:"exporters" yaml:"exporters"`
+	Alerts          AlertsConfig        `json:"alerts" yaml:"alerts"`
+	Notifications   NotificationsConfig `json:"notifications" yaml:"notifications"`
+	Auth            AuthConfig          `json:"auth" yaml:"auth"`
+	RBAC            RBACConfig          `json:"rbac" yaml:"rbac"`
+	TLS             TLSConfig           `json:"tls" yaml:"tls"`
+	Fleet           FleetConfig         `json:"fleet" yaml:"fleet"`
+	Agent           AgentConfig         `json:"agent" yaml:"agent"`
+}
+
+//DockerConfig selects the docker host, DOCKER_HOST and related environment variables are used when it is empty,
+//several named engines can be monitored instead, the first one is the default
+type DockerConfig struct {
+	Host    string                  `json:"host" yaml:"host"`
+	Engines []domain.DockerEndpoint `json:"engines" yaml:"engines"`
+}
+
+//Endpoints returns the configured engines or the local one of Host
+func (d DockerConfig) Endpoints() []domain.DockerEndpoint {
+	if len(d.Engines) > 0 {
+		return d.Engines
+	}
+
+	return []domain.DockerEndpoint{{Name: defaultEngineName, Host: d.Host}}
+}
+
+//MetricsConfig keeps History of samples in memory,
+//or persists them under DataDir rolling them up by the retention tiers when it is set
+type MetricsConfig struct {
+	Interval  time.Duration          `json:"interval" yaml:"interval"`
+	History   time.Duration          `json:"history" yaml:"history"`
+	DataDir   string                 `json:"dataDir" yaml:"dataDir"`
+	Retention []domain.RetentionTier `json:"retention" yaml:"retention"`
+}
+
+type EventsConfig struct {
+	Capacity int `json:"capacity" yaml:"capacity"`
+}
+
+type ExportersConfig struct {
+	Prometheus PrometheusConfig `json:"prometheus" yaml:"prometheus"`
+}
+
+type PrometheusConfig struct {
+	Enabled bool   `json:"enabled" yaml:"enabled"`
+	Path    string `json:"path" yaml:"path"`
+}
+
+type AlertsConfig struct {
+	Interval time.Duration      `json:"interval" yaml:"interval"`
+	Rules    []domain.AlertRule `json:"rules" yaml:"rules"`
+}
+
+type NotificationsConfig struct {
+	Retries   int               `json:"retries" yaml:"retries"`
+	Backoff   time.Duration     `json:"backoff" yaml:"backoff"`
+	Timeout   time.Duration     `json:"timeout" yaml:"timeout"`
+	Receivers []domain.Receiver `json:"receivers" yaml:"receivers"`
+}
+
+//AuthConfig enables authentication methods in the order they are tried,
+//no methods disable authentication unless tls.clientCA adds the cert method
+type AuthConfig struct {
+	Methods  []string      `json:"methods" yaml:"methods"`
+	Tokens   []TokenConfig `json:"tokens" yaml:"tokens"`
+	Htpasswd string        `json:"htpasswd" yaml:"htpasswd"`
+}
+
+type TokenConfig struct {
+	Name  string `json:"name" yaml:"name"`
+	Token string `json:"token" yaml:"token"`
+}
+
+//RBACConfig binds roles to principals, no bindings disable access control
+type RBACConfig struct {
+	Bindings []domain.RoleBinding `json:"bindings" yaml:"bindings"`
+}
+
+//TLSConfig enables HTTPS when a certificate is set,
+//client certificates signed by ClientCA are required when it is set except by probes,
+//their subject is then the principal unless another auth method identifies the caller first
+type TLSConfig struct {
+	Cert     string `json:"cert" yaml:"cert"`
+	Key      string `json:"key" yaml:"key"`
+	ClientCA string `json:"clientCA" yaml:"clientCA"`
+}
+
+//FleetConfig names this host in the fleet, the server marks nodes stale when they do not report for StaleAfter
+type FleetConfig struct {
+	Node       string        `json:"node" yaml:"node"`
+	StaleAfter time.Duration `json:"staleAfter" yaml:"staleAfter"`
+}
+
+//AgentConfig is used by the agent mode to report to the fleet server,
+//the server is trusted by the system roots or TLSCA and the token or the client certificate authenticate the agent
+type AgentConfig struct {
+	Server  string        `json:"server" yaml:"server"`
+	Token   string        `json:"token" yaml:"token"`
+	TLSCA   string        `json:"tlsCA" yaml:"tlsCA"`
+	TLSCert string        `json:"tlsCert" yaml:"tlsCert"`
+	TLSKey  string        `json:"tlsKey" yaml:"tlsKey"`
+	Timeout time.Duration `json:"timeout" yaml:"timeout"`
+}
+
+//Default returns configuration used when no file is given
+func Default() *Config {
+	return &Config{
+		Listen:          defaultListen,
+		ShutdownTimeout: defaultShutdownTimeout,
+		ShutdownDelay:   defaultShutdownDelay,
+		Metrics: MetricsConfig{
+			Interval:  defaultMetricsInterval,
+			History:   defaultHistory,
+			Retention: append([]domain.RetentionTier(nil), defaultRetention...),
+		},
+		Events: EventsConfig{
+			Capacity: defaultEventsCapacity,
+		},
+		Exporters: ExportersConfig{
+			Prometheus: PrometheusConfig{
+				Enabled: true,
+				Path:    defaultMetricsPath,
+			},
+		},
+		Alerts: AlertsConfig{
+			Interval: defaultAlertsInterval,
+		},
+		Notifications: NotificationsConfig{
+			Retries: defaultRetries,
+			Backoff: defaultBackoff,
+			Timeout: defaultWebhookTimeout,
+		},
+		Fleet: FleetConfig{
+			Node:       hostname(),
+			StaleAfter: defaultStaleAfter,
+		},
+		Agent: AgentConfig{
+			Timeout: defaultAgentTimeout,
+		},
+	}
+}
+
+func hostname() string {
+	name, err := os.Hostname()
+	if err != nil {
+		return "localhost"
+	}
+
+	return name
+}
+
+//Load reads a YAML configuration file, or a TOML one with the .toml extension, on top of the defaults
+func Load(path string) (*Config, error) {
+	result := Default()
+	if path == "" {
+		return result, nil
+	}
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.EqualFold(filepath.Ext(path), ".toml") {
+		if content, err = tomlToYAML(content); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	if err := yaml.UnmarshalStrict(content, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+//tomlToYAML converts a TOML document to YAML, so both formats share field names, durations and strict checks
+func tomlToYAML(content []byte) ([]byte, error) {
+	var document map[string]interface{}
+	if err := toml.Unmarshal(content, &document); err != nil {
+		return nil, err
+	}
+
+	return yaml.Marshal(document)
+}
+
+//Document returns the configuration with the fields and values of configuration files,
+//so durations are written like 10s instead of nanoseconds
+func (c Config) Document() (map[string]interface{}, error) {
+	content, err := yaml.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+
+	var document map[interface{}]interface{}
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		return nil, err
+	}
+
+	return stringKeys(document).(map[string]interface{}), nil
+}
+
+//stringKeys converts maps decoded from YAML to maps with string keys which can be written as JSON
+func stringKeys(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{}, len(value))
+		for key, item := range value {
+			result[fmt.Sprint(key)] = stringKeys(item)
+		}
+		return result
+	case []interface{}:
+		for i, item := range value {
+			value[i] = stringKeys(item)
+		}
+		return value
+	default:
+		return value
+	}
+}
+
+//Validate checks settings which do not depend on other components
+func (c *Config) Validate() error {
+	if c.Listen == "" {
+		return errors.New("listen address is required")
+	}
+	if c.ShutdownTimeout <= 0 {
+		return errors.New("shutdownTimeout must be positive")
+	}
+	if c.ShutdownDelay < 0 {
+		return errors.New("shutdownDelay must not be negative")
+	}
+	if c.Docker.Host != "" && len(c.Docker.Engines) > 0 {
+		return errors.New("docker.host cannot be combined with docker.engines, add it as an engine instead")
+	}
+	if c.Metrics.Interval <= 0 {
+		return errors.New("metrics.interval must be positive")
+	}
+	if c.Metrics.History < c.Metrics.Interval {
+		return errors.New("metrics.history must not be shorter than metrics.interval")
+	}
+	if c.Metrics.DataDir != "" {
+		if err := validateRetention(c.Metrics.Retention); err != nil {
+			return err
+		}
+	}
+	if c.Events.Capacity <= 0 {
+		return errors.New("events.capacity must be positive")
+	}
+	if c.Exporters.Prometheus.Enabled && !strings.HasPrefix(c.Exporters.Prometheus.Path, "/") {
+		return errors.New("exporters.prometheus.path must start with /")
+	}
+	if c.Alerts.Interval <= 0 {
+		return errors.New("alerts.interval must be positive")
+	}
+	if c.Notifications.Retries < 0 || c.Notifications.Backoff < 0 || c.Notifications.Timeout <= 0 {
+		return errors.New("notifications retries and backoff must not be negative and timeout must be positive")
+	}
+	if c.Fleet.Node == "" {
+		return errors.New("fleet.node is required")
+	}
+	if c.Fleet.StaleAfter < minStaleAfter || c.Fleet.StaleAfter < 2*c.Metrics.Interval {
+		return fmt.Errorf("fleet.staleAfter must be at least %s and twice metrics.interval", minStaleAfter)
+	}
+	if (c.TLS.Cert == "") != (c.TLS.Key == "") {
+		return errors.New("tls requires both a certificate and a key file")
+	}
+	if c.TLS.ClientCA != "" && c.TLS.Cert == "" {
+		return errors.New("tls.clientCA requires tls.cert and tls.key")
+	}
+
+	return nil
+}
+
+//ValidateAgent checks settings of the agent mode
+func (c *Config) ValidateAgent() error {
+	server, err := url.Parse(c.Agent.Server)
+	if err != nil || (server.Scheme != "http" && server.Scheme != "https") || server.Host == "" {
+		return errors.New("agent.server must be an http or https url of the fleet server")
+	}
+	if c.Agent.Timeout <= 0 {
+		return errors.New("agent.timeout must be positive")
+	}
+	if (c.Agent.TLSCert == "") != (c.Agent.TLSKey == "") {
+		return errors.New("agent requires both a client certificate and a key file")
+	}
+
+	return nil
+}
+
+//validateRetention checks that tiers start with raw samples and get coarser and longer
+func validateRetention(tiers []domain.RetentionTier) error {
+	if len(tiers) == 0 {
+		return errors.New("metrics.retention requires at least one tier")
+	}
+	if tiers[0].Resolution != 0 {
+		return errors.New("metrics.retention: first tier must keep raw samples with zero resolution")
+	}
+
+	for i, tier := range tiers {
+		if tier.Retention <= 0 {
+			return fmt.Errorf("metrics.retention tier %d: retention must be positive", i)
+		}
+		if i > 0 && (tier.Resolution <= tiers[i-1].Resolution || tier.Retention <= tiers[i-1].Retention) {
+			return fmt.Errorf("metrics.retention tier %d: resolution and retention must be greater than of the previous tier", i)
+		}
+	}
+
+	return nil
+}
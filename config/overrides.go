@@ -0,0 +1,187 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	configEnv      = "GODTOP_CONFIG"
+	legacyDebugEnv = "GO_SERVER_DEBUG"
+)
+
+//setting is a configuration value which can be overridden by an environment variable and a command line flag,
+//secrets have no flag since command lines are visible to other users
+type setting struct {
+	flag    string
+	env     string
+	usage   string
+	boolean bool
+	apply   func(c *Config, value string) error
+}
+
+var settings = []setting{
+	{flag: "listen", env: "GODTOP_LISTEN", usage: "`address` to listen on",
+		apply: func(c *Config, value string) error { c.Listen = value; return nil }},
+	{flag: "shutdown-timeout", env: "GODTOP_SHUTDOWN_TIMEOUT", usage: "`duration` to drain requests and stop components on shutdown",
+		apply: func(c *Config, value string) error { return setDuration(&c.ShutdownTimeout, value) }},
+	{flag: "shutdown-delay", env: "GODTOP_SHUTDOWN_DELAY", usage: "`duration` to fail readiness before draining requests on shutdown",
+		apply: func(c *Config, value string) error { return setDuration(&c.ShutdownDelay, value) }},
+	{flag: "debug", env: "GODTOP_DEBUG", usage: "log debug messages", boolean: true,
+		apply: func(c *Config, value string) error { return setBool(&c.Debug, value) }},
+	{flag: "docker-host", env: "GODTOP_DOCKER_HOST", usage: "docker daemon `address`",
+		apply: func(c *Config, value string) error { c.Docker.Host = value; return nil }},
+	{flag: "interval", env: "GODTOP_INTERVAL", usage: "metrics collection `interval`",
+		apply: func(c *Config, value string) error { return setDuration(&c.Metrics.Interval, value) }},
+	{flag: "history", env: "GODTOP_HISTORY", usage: "`duration` of metrics history kept in memory",
+		apply: func(c *Config, value string) error { return setDuration(&c.Metrics.History, value) }},
+	{flag: "data-dir", env: "GODTOP_DATA_DIR", usage: "`directory` to persist metrics in",
+		apply: func(c *Config, value string) error { c.Metrics.DataDir = value; return nil }},
+	{flag: "alerts-interval", env: "GODTOP_ALERTS_INTERVAL", usage: "alert rules evaluation `interval`",
+		apply: func(c *Config, value string) error { return setDuration(&c.Alerts.Interval, value) }},
+	{flag: "prometheus", env: "GODTOP_PROMETHEUS", usage: "serve prometheus metrics", boolean: true,
+		apply: func(c *Config, value string) error { return setBool(&c.Exporters.Prometheus.Enabled, value) }},
+	{flag: "auth", env: "GODTOP_AUTH", usage: "comma separated authentication `methods`: token, basic, cert",
+		apply: func(c *Config, value string) error { c.Auth.Methods = splitList(value); return nil }},
+	{flag: "htpasswd", env: "GODTOP_HTPASSWD", usage: "htpasswd `file` of the basic authentication",
+		apply: func(c *Config, value string) error { c.Auth.Htpasswd = value; return nil }},
+	{flag: "tls-cert", env: "GODTOP_TLS_CERT", usage: "TLS certificate `file`",
+		apply: func(c *Config, value string) error { c.TLS.Cert = value; return nil }},
+	{flag: "tls-key", env: "GODTOP_TLS_KEY", usage: "TLS private key `file`",
+		apply: func(c *Config, value string) error { c.TLS.Key = value; return nil }},
+	{flag: "node", env: "GODTOP_NODE", usage: "`name` of this host in the fleet",
+		apply: func(c *Config, value string) error { c.Fleet.Node = value; return nil }},
+	{flag: "stale-after", env: "GODTOP_STALE_AFTER", usage: "`duration` without reports after which fleet nodes are stale",
+		apply: func(c *Config, value string) error { return setDuration(&c.Fleet.StaleAfter, value) }},
+	{flag: "server", env: "GODTOP_AGENT_SERVER", usage: "fleet server `url` the agent reports to",
+		apply: func(c *Config, value string) error { c.Agent.Server = value; return nil }},
+	{env: "GODTOP_AGENT_TOKEN", usage: "token the agent authenticates with",
+		apply: func(c *Config, value string) error { c.Agent.Token = value; return nil }},
+	{flag: "tls-client-ca", env: "GODTOP_TLS_CLIENT_CA", usage: "CA bundle `file` to verify client certificates",
+		apply: func(c *Config, value string) error { c.TLS.ClientCA = value; return nil }},
+}
+
+//flagValue keeps the raw flag value until the file and environment variables are applied
+type flagValue struct {
+	value   string
+	boolean bool
+}
+
+func (v *flagValue) String() string {
+	if v == nil {
+		return ""
+	}
+	return v.value
+}
+
+func (v *flagValue) Set(value string) error {
+	v.value = value
+	return nil
+}
+
+func (v *flagValue) IsBoolFlag() bool {
+	return v.boolean
+}
+
+//Parse reads the configuration file, then applies environment variables and then command line flags,
+//the file is given by the -config flag or GODTOP_CONFIG
+func Parse(name string, args []string) (*Config, error) {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	path := flags.String("config", os.Getenv(configEnv), "configuration `file` ($"+configEnv+")")
+
+	values := make(map[string]*flagValue, len(settings))
+	for _, s := range settings {
+		if s.flag == "" {
+			continue
+		}
+		values[s.flag] = &flagValue{boolean: s.boolean}
+		flags.Var(values[s.flag], s.flag, fmt.Sprintf("%s ($%s)", s.usage, s.env))
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+	if flags.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected arguments: %s", strings.Join(flags.Args(), " "))
+	}
+
+	result, err := Load(*path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := result.applyEnv(); err != nil {
+		return nil, err
+	}
+
+	var flagErr error
+	flags.Visit(func(f *flag.Flag) {
+		for _, s := range settings {
+			if s.flag == f.Name && flagErr == nil {
+				if err := s.apply(result, values[s.flag].value); err != nil {
+					flagErr = fmt.Errorf("-%s: %s", s.flag, err)
+				}
+			}
+		}
+	})
+	if flagErr != nil {
+		return nil, flagErr
+	}
+
+	return result, nil
+}
+
+//applyEnv overrides settings by the environment variables which are set,
+//GO_SERVER_DEBUG is still honoured for compatibility
+func (c *Config) applyEnv() error {
+	if os.Getenv(legacyDebugEnv) != "" {
+		c.Debug = true
+	}
+
+	for _, s := range settings {
+		value, ok := os.LookupEnv(s.env)
+		if !ok {
+			continue
+		}
+		if err := s.apply(c, value); err != nil {
+			return fmt.Errorf("%s: %s", s.env, err)
+		}
+	}
+
+	return nil
+}
+
+func setBool(target *bool, value string) error {
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return fmt.Errorf("invalid boolean %q", value)
+	}
+
+	*target = parsed
+	return nil
+}
+
+func setDuration(target *time.Duration, value string) error {
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return err
+	}
+
+	*target = parsed
+	return nil
+}
+
+func splitList(value string) []string {
+	var result []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+
+	return result
+}
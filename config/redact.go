@@ -0,0 +1,77 @@
+package config
+
+import (
+	"godtop/domain"
+	"net/url"
+	"strings"
+)
+
+const redacted = "******"
+
+//Redacted returns a copy of the configuration safe to expose,
+//auth tokens are masked and receiver URLs keep only the scheme and host since webhook paths often carry credentials,
+//docker hosts and the fleet server lose credentials of their URLs
+func (c Config) Redacted() Config {
+	tokens := make([]TokenConfig, len(c.Auth.Tokens))
+	for i, token := range c.Auth.Tokens {
+		tokens[i] = TokenConfig{Name: token.Name, Token: redacted}
+	}
+	c.Auth.Tokens = tokens
+
+	if c.Agent.Token != "" {
+		c.Agent.Token = redacted
+	}
+	c.Agent.Server = redactUserinfo(c.Agent.Server)
+
+	c.Docker.Host = redactUserinfo(c.Docker.Host)
+	engines := make([]domain.DockerEndpoint, len(c.Docker.Engines))
+	for i, engine := range c.Docker.Engines {
+		engine.Host = redactUserinfo(engine.Host)
+		engines[i] = engine
+	}
+	c.Docker.Engines = engines
+
+	receivers := make([]domain.Receiver, len(c.Notifications.Receivers))
+	for i, receiver := range c.Notifications.Receivers {
+		receiver.URL = redactURL(receiver.URL)
+		if receiver.Secret != "" {
+			receiver.Secret = redacted
+		}
+		receivers[i] = receiver
+	}
+	c.Notifications.Receivers = receivers
+
+	return c
+}
+
+//redactUserinfo masks the user and password of the URL
+func redactUserinfo(raw string) string {
+	if raw == "" {
+		return raw
+	}
+
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return redacted
+	}
+	if parsed.User == nil {
+		return raw
+	}
+
+	//the URL would escape the mask
+	parsed.User = nil
+	return strings.Replace(parsed.String(), "://", "://"+redacted+"@", 1)
+}
+
+func redactURL(raw string) string {
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.Host == "" {
+		return redacted
+	}
+
+	if parsed.User == nil && parsed.RawQuery == "" && (parsed.Path == "" || parsed.Path == "/") {
+		return raw
+	}
+
+	return parsed.Scheme + "://" + parsed.Host + "/" + redacted
+}
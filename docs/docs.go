@@ -1,23 +1,21 @@
 // GENERATED BY THE COMMAND ABOVE; DO NOT EDIT
-// This file was generated by swaggo/swag
+// This file was generated by swaggo/swag at
+// 2026-10-18 05:00:01.008786955 +0000 UTC m=+0.096670229
 
 package docs
 
 import (
 	"bytes"
-	"encoding/json"
-	"strings"
 
 	"github.com/alecthomas/template"
 	"github.com/swaggo/swag"
 )
 
 var doc = `{
-    "schemes": {{ marshal .Schemes }},
     "swagger": "2.0",
     "info": {
-        "description": "{{.Description}}",
-        "title": "{{.Title}}",
+        "description": "Docker graphical activity monitor, every route is also served under /api/v2 with responses in an envelope of data, meta and errors",
+        "title": "Godtop",
         "contact": {
             "name": "Aleksey Fishchev",
             "email": "mrfishchev@seniorvlogger.com"
@@ -26,13 +24,80 @@ var doc = `{
             "name": "MIT",
             "url": "https://github.com/MrFishchev/godtop/blob/main/LICENSE"
         },
-        "version": "{{.Version}}"
+        "version": "1.0"
     },
     "host": "{{.Host}}",
-    "basePath": "{{.BasePath}}",
+    "basePath": "/api",
     "paths": {
+        "/alerts": {
+            "get": {
+                "description": "/api/v2 responds in the envelope, data.alerts holds domain.Alert list",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Retrieves pending, firing and recently resolved alerts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "pending, firing or resolved",
+                        "name": "state",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Alert"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/alerts/rules": {
+            "get": {
+                "description": "/api/v2 responds in the envelope, data.rules holds domain.AlertRule list",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Retrieves configured alert rules",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.AlertRule"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/config": {
+            "get": {
+                "description": "/api/v2 responds in the envelope, data.config holds config.Config",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Retrieves the effective configuration with secrets redacted, durations are written like in configuration files",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/config.Config"
+                        }
+                    }
+                }
+            }
+        },
         "/container/{nameOrId}": {
             "get": {
+                "description": "/api/v2 responds in the envelope, data holds domain.ContainerDetails",
                 "produces": [
                     "application/json"
                 ],
@@ -50,120 +115,1876 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/domain.ContainerDetails"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "/api/v2 responds in the envelope, data holds domain.Container",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Removes a container",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "container Name or Id",
+                        "name": "nameOrId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "kill the container if it is running",
+                        "name": "force",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "remove anonymous volumes of the container",
+                        "name": "volumes",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/domain.Container"
+                        }
+                    }
+                }
+            }
+        },
+        "/container/{nameOrId}/kill": {
+            "post": {
+                "description": "/api/v2 responds in the envelope, data holds domain.Container",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Sends a signal to a container",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "container Name or Id",
+                        "name": "nameOrId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "signal to send, SIGKILL by default",
+                        "name": "signal",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/domain.Container"
+                        }
+                    }
+                }
+            }
+        },
+        "/container/{nameOrId}/logs": {
+            "get": {
+                "description": "/api/v2 responds in the envelope, data.logs holds domain.LogEntry list, followed logs are not enveloped",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Retrieves logs of a container, follow streams them as JSON lines or over WebSocket",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "container Name or Id",
+                        "name": "nameOrId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "number of lines from the end of logs, 100 by default, all or more than 10000 only when logs are streamed",
+                        "name": "tail",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "show logs since timestamp or relative duration, e.g. 10m",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "show logs before timestamp or relative duration, e.g. 10m",
+                        "name": "until",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "parse timestamps of log lines",
+                        "name": "timestamps",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "keep streaming new log lines",
+                        "name": "follow",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.LogEntry"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/container/{nameOrId}/pause": {
+            "post": {
+                "description": "/api/v2 responds in the envelope, data holds domain.Container",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Pauses all processes of a container",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "container Name or Id",
+                        "name": "nameOrId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/domain.Container"
+                        }
+                    }
+                }
+            }
+        },
+        "/container/{nameOrId}/restart": {
+            "post": {
+                "description": "/api/v2 responds in the envelope, data holds domain.Container",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Restarts a container, killing it after the timeout",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "container Name or Id",
+                        "name": "nameOrId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "seconds or duration to wait before killing the container",
+                        "name": "timeout",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/domain.Container"
+                        }
+                    }
+                }
+            }
+        },
+        "/container/{nameOrId}/start": {
+            "post": {
+                "description": "/api/v2 responds in the envelope, data holds domain.Container",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Starts a container",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "container Name or Id",
+                        "name": "nameOrId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
                             "$ref": "#/definitions/domain.Container"
                         }
                     }
                 }
             }
         },
-        "/container/{nameOrId}/stats": {
-            "get": {
-                "produces": [
-                    "application/json"
-                ],
-                "summary": "Retrieves statistics of a container",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "container Name or Id",
-                        "name": "nameOrId",
-                        "in": "path",
-                        "required": true
+        "/container/{nameOrId}/stats": {
+            "get": {
+                "description": "/api/v2 responds in the envelope, data.stats holds domain.ContainerStats",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Retrieves statistics of a container",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "container Name or Id",
+                        "name": "nameOrId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/interfaces.legacyContainerStats"
+                        }
+                    }
+                }
+            }
+        },
+        "/container/{nameOrId}/stats/history": {
+            "get": {
+                "description": "/api/v2 responds in the envelope, data.history holds domain.MetricsBucket list",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Retrieves sampled statistics of a container",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "container Name or Id",
+                        "name": "nameOrId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "engine of the container, the default one when not set",
+                        "name": "engine",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "range start, RFC3339 or unix seconds, defaults to an hour ago",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "range end, RFC3339 or unix seconds, defaults to now",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "downsampling step, e.g. 1m",
+                        "name": "step",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.MetricsBucket"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/container/{nameOrId}/stats/ws": {
+            "get": {
+                "description": "/api/v2 sends domain.ContainerStats frames which are not enveloped",
+                "summary": "Streams statistics of a container over WebSocket every second",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "container Name or Id",
+                        "name": "nameOrId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/interfaces.legacyContainerStats"
+                        }
+                    }
+                }
+            }
+        },
+        "/container/{nameOrId}/stop": {
+            "post": {
+                "description": "/api/v2 responds in the envelope, data holds domain.Container",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Stops a container, killing it after the timeout",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "container Name or Id",
+                        "name": "nameOrId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "seconds or duration to wait before killing the container",
+                        "name": "timeout",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/domain.Container"
+                        }
+                    }
+                }
+            }
+        },
+        "/container/{nameOrId}/timeline": {
+            "get": {
+                "description": "/api/v2 responds in the envelope, data.timeline holds domain.ContainerEvent list",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Retrieves recorded lifecycle events of a container",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "container Name or Id",
+                        "name": "nameOrId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "engine of the container, the default one when not set",
+                        "name": "engine",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.ContainerEvent"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/container/{nameOrId}/unpause": {
+            "post": {
+                "description": "/api/v2 responds in the envelope, data holds domain.Container",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Resumes all processes of a container",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "container Name or Id",
+                        "name": "nameOrId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/domain.Container"
+                        }
+                    }
+                }
+            }
+        },
+        "/containers": {
+            "get": {
+                "description": "/api/v2 responds in the envelope, data.containers holds domain.Container list and errors lists engines which failed when all engines are selected",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Retrieves running containers",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "engine name, * merges containers of all engines",
+                        "name": "engine",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Container"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/containers/stats": {
+            "get": {
+                "description": "/api/v2 responds in the envelope, data.stats holds domain.ContainerStats by container id and errors lists containers which failed",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Samples statistics of all running containers concurrently, containers which failed are listed in errors by id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "deadline of the sampling, seconds or duration, 5s by default and at most 30s",
+                        "name": "timeout",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "a single element with statistics and errors by container id",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/interfaces.legacyContainersStats"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/engines": {
+            "get": {
+                "description": "/api/v2 responds in the envelope, data.engines holds engine names",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Retrieves names of the docker engines, the first one is the default",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/events": {
+            "get": {
+                "description": "/api/v2 responds in the envelope, data.events holds domain.ContainerEvent list",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Retrieves recorded lifecycle events of containers of every engine",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "engine name, events of every engine when not set",
+                        "name": "engine",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "container Id",
+                        "name": "container",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "container Name",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma separated actions, e.g. die,oom",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC3339 or unix seconds",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC3339 or unix seconds",
+                        "name": "until",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "return only the latest events",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.ContainerEvent"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/events/stream": {
+            "get": {
+                "description": "/api/v2 sends the same events which are not enveloped",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "summary": "Streams live lifecycle events of containers of every engine as Server-Sent Events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "engine name, events of every engine when not set",
+                        "name": "engine",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "container Id",
+                        "name": "container",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "container Name",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma separated actions, e.g. die,oom",
+                        "name": "action",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/domain.ContainerEvent"
+                        }
+                    }
+                }
+            }
+        },
+        "/fleet/containers": {
+            "get": {
+                "description": "/api/v2 responds in the envelope, data.containers holds domain.ContainerSnapshot list",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Retrieves containers of all nodes with their latest statistics",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "include containers of stale nodes",
+                        "name": "stale",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/interfaces.legacyContainerSnapshot"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/fleet/ingest": {
+            "post": {
+                "description": "/api/v2 responds in the envelope, data holds domain.Node",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Registers a snapshot pushed by a godtop agent, a node name belongs to the principal which reported it first",
+                "parameters": [
+                    {
+                        "description": "node snapshot",
+                        "name": "report",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/domain.NodeReport"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/interfaces.legacyNode"
+                        }
+                    }
+                }
+            }
+        },
+        "/fleet/nodes": {
+            "get": {
+                "description": "/api/v2 responds in the envelope, data.nodes holds domain.Node list",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Retrieves nodes of the fleet with their host information and status",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/interfaces.legacyNode"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/fleet/nodes/{node}": {
+            "get": {
+                "description": "/api/v2 responds in the envelope, data holds domain.Node and domain.MetricsSnapshot",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Retrieves a node of the fleet with its latest snapshot",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "node name",
+                        "name": "node",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/interfaces.legacyMetricsSnapshot"
+                        }
+                    }
+                }
+            }
+        },
+        "/health": {
+            "get": {
+                "description": "/api/v2 responds in the envelope, data.engines holds domain.EngineStatus list",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Retrieves connection status of the docker engines, degraded when any of them is disconnected",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.EngineStatus"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/host": {
+            "get": {
+                "description": "/api/v2 responds in the envelope, data holds domain.HostInfo",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Retrieves information about host stystem",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/interfaces.legacyHostInfo"
+                        }
+                    }
+                }
+            }
+        },
+        "/host/events": {
+            "get": {
+                "description": "/api/v2 sends domain.HostInfo events which are not enveloped",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "summary": "Streams information about host system as Server-Sent Events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "sampling interval, e.g. 2s, snapped down to 500ms, 1s, 2s, 5s, 10s, 30s or 1m",
+                        "name": "interval",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/interfaces.legacyHostInfo"
+                        }
+                    }
+                }
+            }
+        },
+        "/host/history": {
+            "get": {
+                "description": "/api/v2 responds in the envelope, data.history holds domain.MetricsBucket list",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Retrieves sampled information about host system",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "range start, RFC3339 or unix seconds, defaults to an hour ago",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "range end, RFC3339 or unix seconds, defaults to now",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "downsampling step, e.g. 1m",
+                        "name": "step",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.MetricsBucket"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications": {
+            "get": {
+                "description": "/api/v2 responds in the envelope, data.deliveries holds domain.Delivery list",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Retrieves the latest webhook deliveries",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Delivery"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/volumes": {
+            "get": {
+                "description": "/api/v2 responds in the envelope, data.volumes holds domain.Volume list",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Retrieves mounted and created volumes",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Volume"
+                            }
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "config.AgentConfig": {
+            "type": "object",
+            "properties": {
+                "server": {
+                    "type": "string"
+                },
+                "timeout": {
+                    "type": "string"
+                },
+                "tlsCA": {
+                    "type": "string"
+                },
+                "tlsCert": {
+                    "type": "string"
+                },
+                "tlsKey": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "config.AlertsConfig": {
+            "type": "object",
+            "properties": {
+                "interval": {
+                    "type": "string"
+                },
+                "rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.AlertRule"
+                    }
+                }
+            }
+        },
+        "config.AuthConfig": {
+            "type": "object",
+            "properties": {
+                "htpasswd": {
+                    "type": "string"
+                },
+                "methods": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "tokens": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/config.TokenConfig"
+                    }
+                }
+            }
+        },
+        "config.Config": {
+            "type": "object",
+            "properties": {
+                "agent": {
+                    "type": "object",
+                    "$ref": "#/definitions/config.AgentConfig"
+                },
+                "alerts": {
+                    "type": "object",
+                    "$ref": "#/definitions/config.AlertsConfig"
+                },
+                "auth": {
+                    "type": "object",
+                    "$ref": "#/definitions/config.AuthConfig"
+                },
+                "debug": {
+                    "type": "boolean"
+                },
+                "docker": {
+                    "type": "object",
+                    "$ref": "#/definitions/config.DockerConfig"
+                },
+                "events": {
+                    "type": "object",
+                    "$ref": "#/definitions/config.EventsConfig"
+                },
+                "exporters": {
+                    "type": "object",
+                    "$ref": "#/definitions/config.ExportersConfig"
+                },
+                "fleet": {
+                    "type": "object",
+                    "$ref": "#/definitions/config.FleetConfig"
+                },
+                "listen": {
+                    "type": "string"
+                },
+                "metrics": {
+                    "type": "object",
+                    "$ref": "#/definitions/config.MetricsConfig"
+                },
+                "notifications": {
+                    "type": "object",
+                    "$ref": "#/definitions/config.NotificationsConfig"
+                },
+                "rbac": {
+                    "type": "object",
+                    "$ref": "#/definitions/config.RBACConfig"
+                },
+                "shutdownDelay": {
+                    "type": "string"
+                },
+                "shutdownTimeout": {
+                    "type": "string"
+                },
+                "tls": {
+                    "type": "object",
+                    "$ref": "#/definitions/config.TLSConfig"
+                }
+            }
+        },
+        "config.DockerConfig": {
+            "type": "object",
+            "properties": {
+                "engines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.DockerEndpoint"
+                    }
+                },
+                "host": {
+                    "type": "string"
+                }
+            }
+        },
+        "config.EventsConfig": {
+            "type": "object",
+            "properties": {
+                "capacity": {
+                    "type": "integer"
+                }
+            }
+        },
+        "config.ExportersConfig": {
+            "type": "object",
+            "properties": {
+                "prometheus": {
+                    "type": "object",
+                    "$ref": "#/definitions/config.PrometheusConfig"
+                }
+            }
+        },
+        "config.FleetConfig": {
+            "type": "object",
+            "properties": {
+                "node": {
+                    "type": "string"
+                },
+                "staleAfter": {
+                    "type": "string"
+                }
+            }
+        },
+        "config.MetricsConfig": {
+            "type": "object",
+            "properties": {
+                "dataDir": {
+                    "type": "string"
+                },
+                "history": {
+                    "type": "string"
+                },
+                "interval": {
+                    "type": "string"
+                },
+                "retention": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.RetentionTier"
+                    }
+                }
+            }
+        },
+        "config.NotificationsConfig": {
+            "type": "object",
+            "properties": {
+                "backoff": {
+                    "type": "string"
+                },
+                "receivers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Receiver"
+                    }
+                },
+                "retries": {
+                    "type": "integer"
+                },
+                "timeout": {
+                    "type": "string"
+                }
+            }
+        },
+        "config.PrometheusConfig": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "path": {
+                    "type": "string"
+                }
+            }
+        },
+        "config.RBACConfig": {
+            "type": "object",
+            "properties": {
+                "bindings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.RoleBinding"
+                    }
+                }
+            }
+        },
+        "config.TLSConfig": {
+            "type": "object",
+            "properties": {
+                "cert": {
+                    "type": "string"
+                },
+                "clientCA": {
+                    "type": "string"
+                },
+                "key": {
+                    "type": "string"
+                }
+            }
+        },
+        "config.TokenConfig": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.Alert": {
+            "type": "object",
+            "properties": {
+                "activeSince": {
+                    "type": "string"
+                },
+                "containerId": {
+                    "type": "string"
+                },
+                "engine": {
+                    "type": "string"
+                },
+                "expr": {
+                    "type": "string"
+                },
+                "firedAt": {
+                    "type": "string"
+                },
+                "resolvedAt": {
+                    "type": "string"
+                },
+                "rule": {
+                    "type": "string"
+                },
+                "severity": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string"
+                },
+                "subject": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.AlertRule": {
+            "type": "object",
+            "properties": {
+                "containers": {
+                    "type": "string"
+                },
+                "expr": {
+                    "type": "string"
+                },
+                "for": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "severity": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.BlockIOStats": {
+            "type": "object",
+            "properties": {
+                "readBytes": {
+                    "type": "integer"
+                },
+                "readOps": {
+                    "type": "integer"
+                },
+                "writeBytes": {
+                    "type": "integer"
+                },
+                "writeOps": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.Container": {
+            "type": "object",
+            "properties": {
+                "engine": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "names": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "node": {
+                    "type": "string"
+                },
+                "publicPorts": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "state": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.ContainerDetails": {
+            "type": "object",
+            "properties": {
+                "command": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "created": {
+                    "type": "string"
+                },
+                "engine": {
+                    "type": "string"
+                },
+                "env": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "exitCode": {
+                    "type": "integer"
+                },
+                "finishedAt": {
+                    "type": "string"
+                },
+                "health": {
+                    "type": "object",
+                    "$ref": "#/definitions/domain.Health"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "image": {
+                    "type": "string"
+                },
+                "labels": {
+                    "type": "object"
+                },
+                "mounts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Mount"
+                    }
+                },
+                "names": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "networks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.ContainerNetwork"
+                    }
+                },
+                "node": {
+                    "type": "string"
+                },
+                "ports": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.PortMapping"
+                    }
+                },
+                "publicPorts": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "resources": {
+                    "type": "object",
+                    "$ref": "#/definitions/domain.ResourceLimits"
+                },
+                "restartCount": {
+                    "type": "integer"
+                },
+                "restartPolicy": {
+                    "type": "object",
+                    "$ref": "#/definitions/domain.RestartPolicy"
+                },
+                "startedAt": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.ContainerEvent": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "containerId": {
+                    "type": "string"
+                },
+                "engine": {
+                    "type": "string"
+                },
+                "exitCode": {
+                    "type": "integer"
+                },
+                "health": {
+                    "type": "string"
+                },
+                "image": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "time": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.ContainerNetwork": {
+            "type": "object",
+            "properties": {
+                "gateway": {
+                    "type": "string"
+                },
+                "ipAddress": {
+                    "type": "string"
+                },
+                "ipv6Address": {
+                    "type": "string"
+                },
+                "macAddress": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.ContainerScope": {
+            "type": "object",
+            "properties": {
+                "labels": {
+                    "type": "object"
+                },
+                "names": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "domain.ContainerSnapshot": {
+            "type": "object",
+            "properties": {
+                "container": {
+                    "type": "object",
+                    "$ref": "#/definitions/domain.Container"
+                },
+                "stats": {
+                    "type": "object",
+                    "$ref": "#/definitions/domain.ContainerStats"
+                }
+            }
+        },
+        "domain.ContainerStats": {
+            "type": "object",
+            "properties": {
+                "blockIO": {
+                    "type": "object",
+                    "$ref": "#/definitions/domain.BlockIOStats"
+                },
+                "cpu": {
+                    "type": "object",
+                    "$ref": "#/definitions/domain.CpuStats"
+                },
+                "cpuUsage": {
+                    "type": "number"
+                },
+                "memory": {
+                    "type": "object",
+                    "$ref": "#/definitions/domain.MemoryStats"
+                },
+                "memoryUsage": {
+                    "type": "number"
+                },
+                "networks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.NetworkStats"
+                    }
+                },
+                "pids": {
+                    "type": "object",
+                    "$ref": "#/definitions/domain.PidsStats"
+                },
+                "rxBytes": {
+                    "type": "integer"
+                },
+                "txBytes": {
+                    "type": "integer"
+                },
+                "usedMemory": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.CpuStats": {
+            "type": "object",
+            "properties": {
+                "kernel": {
+                    "type": "number"
+                },
+                "onlineCpus": {
+                    "type": "integer"
+                },
+                "perCore": {
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "periods": {
+                    "type": "integer"
+                },
+                "quota": {
+                    "type": "number"
+                },
+                "quotaUsage": {
+                    "type": "number"
+                },
+                "throttledPeriods": {
+                    "type": "integer"
+                },
+                "throttledTime": {
+                    "type": "integer"
+                },
+                "user": {
+                    "type": "number"
+                }
+            }
+        },
+        "domain.Delivery": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "receiver": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "statusCode": {
+                    "type": "integer"
+                },
+                "time": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.DockerEndpoint": {
+            "type": "object",
+            "properties": {
+                "host": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "tlsCA": {
+                    "type": "string"
+                },
+                "tlsCert": {
+                    "type": "string"
+                },
+                "tlsKey": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.EngineStatus": {
+            "type": "object",
+            "properties": {
+                "apiVersion": {
+                    "type": "string"
+                },
+                "connected": {
+                    "type": "boolean"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "host": {
+                    "type": "string"
+                },
+                "lastCheck": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "serverVersion": {
+                    "type": "string"
+                },
+                "since": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.Health": {
+            "type": "object",
+            "properties": {
+                "failingStreak": {
+                    "type": "integer"
+                },
+                "lastOutput": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.HostInfo": {
+            "type": "object",
+            "properties": {
+                "cpuUsage": {
+                    "type": "number"
+                },
+                "totalMemory": {
+                    "type": "integer"
+                },
+                "totalStorage": {
+                    "type": "integer"
+                },
+                "totalSwapMemory": {
+                    "type": "integer"
+                },
+                "usedMemory": {
+                    "type": "integer"
+                },
+                "usedStorage": {
+                    "type": "integer"
+                },
+                "usedSwapMemory": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.LogEntry": {
+            "type": "object",
+            "properties": {
+                "line": {
+                    "type": "string"
+                },
+                "stream": {
+                    "type": "string"
+                },
+                "timestamp": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.MemoryStats": {
+            "type": "object",
+            "properties": {
+                "cache": {
+                    "type": "integer"
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "rss": {
+                    "type": "integer"
+                },
+                "swap": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.MetricsBucket": {
+            "type": "object",
+            "properties": {
+                "avg": {
+                    "type": "object"
+                },
+                "count": {
+                    "type": "integer"
+                },
+                "max": {
+                    "type": "object"
+                },
+                "min": {
+                    "type": "object"
+                },
+                "time": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.MetricsSnapshot": {
+            "type": "object",
+            "properties": {
+                "containers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.ContainerSnapshot"
                     }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/domain.ContainerStats"
-                        }
+                },
+                "failedEngines": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
                     }
+                },
+                "host": {
+                    "type": "object",
+                    "$ref": "#/definitions/domain.HostInfo"
+                },
+                "time": {
+                    "type": "string"
                 }
             }
         },
-        "/containers": {
-            "get": {
-                "produces": [
-                    "application/json"
-                ],
-                "summary": "Retrieves running containers",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/domain.Container"
-                            }
-                        }
-                    }
+        "domain.Mount": {
+            "type": "object",
+            "properties": {
+                "destination": {
+                    "type": "string"
+                },
+                "mode": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "readOnly": {
+                    "type": "boolean"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
                 }
             }
         },
-        "/host": {
-            "get": {
-                "produces": [
-                    "application/json"
-                ],
-                "summary": "Retrieves information about host stystem",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/domain.HostInfo"
-                        }
-                    }
+        "domain.NetworkStats": {
+            "type": "object",
+            "properties": {
+                "interface": {
+                    "type": "string"
+                },
+                "rxBytes": {
+                    "type": "integer"
+                },
+                "rxDropped": {
+                    "type": "integer"
+                },
+                "rxErrors": {
+                    "type": "integer"
+                },
+                "rxPackets": {
+                    "type": "integer"
+                },
+                "txBytes": {
+                    "type": "integer"
+                },
+                "txDropped": {
+                    "type": "integer"
+                },
+                "txErrors": {
+                    "type": "integer"
+                },
+                "txPackets": {
+                    "type": "integer"
                 }
             }
         },
-        "/volumes": {
-            "get": {
-                "produces": [
-                    "application/json"
-                ],
-                "summary": "Retrieves mounted and created volumes",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/domain.Volume"
-                            }
-                        }
-                    }
+        "domain.NodeReport": {
+            "type": "object",
+            "properties": {
+                "node": {
+                    "type": "string"
+                },
+                "snapshot": {
+                    "type": "object",
+                    "$ref": "#/definitions/domain.MetricsSnapshot"
                 }
             }
-        }
-    },
-    "definitions": {
-        "domain.Container": {
+        },
+        "domain.PidsStats": {
             "type": "object",
             "properties": {
-                "id": {
+                "current": {
+                    "type": "integer"
+                },
+                "limit": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.PortMapping": {
+            "type": "object",
+            "properties": {
+                "hostIp": {
                     "type": "string"
                 },
-                "names": {
+                "privatePort": {
+                    "type": "integer"
+                },
+                "protocol": {
+                    "type": "string"
+                },
+                "publicPort": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.Receiver": {
+            "type": "object",
+            "properties": {
+                "events": {
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
                 },
-                "publicPorts": {
+                "format": {
+                    "type": "string"
+                },
+                "ignoreAlerts": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.ResourceLimits": {
+            "type": "object",
+            "properties": {
+                "cpuPeriod": {
+                    "type": "integer"
+                },
+                "cpuQuota": {
+                    "type": "integer"
+                },
+                "cpuShares": {
+                    "type": "integer"
+                },
+                "cpusetCpus": {
+                    "type": "string"
+                },
+                "memory": {
+                    "type": "integer"
+                },
+                "memorySwap": {
+                    "type": "integer"
+                },
+                "nanoCpus": {
+                    "type": "integer"
+                },
+                "pidsLimit": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.RestartPolicy": {
+            "type": "object",
+            "properties": {
+                "maximumRetryCount": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.RetentionTier": {
+            "type": "object",
+            "properties": {
+                "resolution": {
+                    "type": "string"
+                },
+                "retention": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.RoleBinding": {
+            "type": "object",
+            "properties": {
+                "containers": {
+                    "type": "object",
+                    "$ref": "#/definitions/domain.ContainerScope"
+                },
+                "principals": {
                     "type": "array",
                     "items": {
-                        "type": "integer"
+                        "type": "string"
                     }
                 },
-                "state": {
+                "role": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.Volume": {
+            "type": "object",
+            "properties": {
+                "destination": {
                     "type": "string"
                 },
-                "status": {
+                "name": {
+                    "type": "string"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "source": {
                     "type": "string"
                 }
             }
         },
-        "domain.ContainerStats": {
+        "interfaces.legacyContainerSnapshot": {
+            "type": "object",
+            "properties": {
+                "container": {
+                    "type": "object",
+                    "$ref": "#/definitions/domain.Container"
+                },
+                "stats": {
+                    "type": "object",
+                    "$ref": "#/definitions/interfaces.legacyContainerStats"
+                }
+            }
+        },
+        "interfaces.legacyContainerStats": {
             "type": "object",
             "properties": {
                 "cpuUsage": {
@@ -183,7 +2004,18 @@ var doc = `{
                 }
             }
         },
-        "domain.HostInfo": {
+        "interfaces.legacyContainersStats": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "object"
+                },
+                "stats": {
+                    "type": "object"
+                }
+            }
+        },
+        "interfaces.legacyHostInfo": {
             "type": "object",
             "properties": {
                 "cpuUsage": {
@@ -209,19 +2041,47 @@ var doc = `{
                 }
             }
         },
-        "domain.Volume": {
+        "interfaces.legacyMetricsSnapshot": {
             "type": "object",
             "properties": {
-                "destination": {
+                "containers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/interfaces.legacyContainerSnapshot"
+                    }
+                },
+                "host": {
+                    "type": "object",
+                    "$ref": "#/definitions/interfaces.legacyHostInfo"
+                },
+                "time": {
+                    "type": "string"
+                }
+            }
+        },
+        "interfaces.legacyNode": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "containers": {
+                    "type": "integer"
+                },
+                "host": {
+                    "type": "object",
+                    "$ref": "#/definitions/interfaces.legacyHostInfo"
+                },
+                "lastSeen": {
                     "type": "string"
                 },
                 "name": {
                     "type": "string"
                 },
-                "size": {
+                "running": {
                     "type": "integer"
                 },
-                "source": {
+                "status": {
                     "type": "string"
                 }
             }
@@ -233,39 +2093,23 @@ type swaggerInfo struct {
 	Version     string
 	Host        string
 	BasePath    string
-	Schemes     []string
 	Title       string
 	Description string
 }
 
 // SwaggerInfo holds exported Swagger Info so clients can modify it
-var SwaggerInfo = swaggerInfo{
-	Version:     "1.0",
-	Host:        "",
-	BasePath:    "/api",
-	Schemes:     []string{},
-	Title:       "Godtop",
-	Description: "Docker graphical activity monitor",
-}
+var SwaggerInfo swaggerInfo
 
 type s struct{}
 
 func (s *s) ReadDoc() string {
-	sInfo := SwaggerInfo
-	sInfo.Description = strings.Replace(sInfo.Description, "\n", "\\n", -1)
-
-	t, err := template.New("swagger_info").Funcs(template.FuncMap{
-		"marshal": func(v interface{}) string {
-			a, _ := json.Marshal(v)
-			return string(a)
-		},
-	}).Parse(doc)
+	t, err := template.New("swagger_info").Parse(doc)
 	if err != nil {
 		return doc
 	}
 
 	var tpl bytes.Buffer
-	if err := t.Execute(&tpl, sInfo); err != nil {
+	if err := t.Execute(&tpl, SwaggerInfo); err != nil {
 		return doc
 	}
 
@@ -0,0 +1,23 @@
+package domain
+
+const (
+	ViewerRole   = "viewer"
+	OperatorRole = "operator"
+	AdminRole    = "admin"
+	//AgentRole only grants pushing fleet reports, agents are bound to it along with viewer when they read too
+	AgentRole = "agent"
+)
+
+//RoleBinding grants a role to principals matching name patterns,
+//a non-empty scope limits the role to routes of matching containers
+type RoleBinding struct {
+	Principals []string       `json:"principals" yaml:"principals"`
+	Role       string         `json:"role" yaml:"role"`
+	Containers ContainerScope `json:"containers" yaml:"containers"`
+}
+
+//ContainerScope matches containers with a name matching any of Names and all of Labels
+type ContainerScope struct {
+	Names  []string          `json:"names,omitempty" yaml:"names"`
+	Labels map[string]string `json:"labels,omitempty" yaml:"labels"`
+}
@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	AlertPending  = "pending"
+	AlertFiring   = "firing"
+	AlertResolved = "resolved"
+)
+
+//AlertRule describes a condition like "container cpuUsage > 80" which fires after holding for a duration,
+//For is encoded as a duration string like "5m0s" as the configuration writes it
+type AlertRule struct {
+	Name       string        `json:"name" yaml:"name"`
+	Expr       string        `json:"expr" yaml:"expr"`
+	For        time.Duration `json:"for" yaml:"for" swaggertype:"string"`
+	Containers string        `json:"containers,omitempty" yaml:"containers"`
+	Severity   string        `json:"severity,omitempty" yaml:"severity"`
+}
+
+//alertRuleJSON is the JSON form of a rule with For as a duration string
+type alertRuleJSON struct {
+	Name       string `json:"name"`
+	Expr       string `json:"expr"`
+	For        string `json:"for"`
+	Containers string `json:"containers,omitempty"`
+	Severity   string `json:"severity,omitempty"`
+}
+
+func (r AlertRule) MarshalJSON() ([]byte, error) {
+	return json.Marshal(alertRuleJSON{
+		Name:       r.Name,
+		Expr:       r.Expr,
+		For:        r.For.String(),
+		Containers: r.Containers,
+		Severity:   r.Severity,
+	})
+}
+
+func (r *AlertRule) UnmarshalJSON(data []byte) error {
+	var rule alertRuleJSON
+	if err := json.Unmarshal(data, &rule); err != nil {
+		return err
+	}
+
+	var duration time.Duration
+	if rule.For != "" {
+		var err error
+		if duration, err = time.ParseDuration(rule.For); err != nil {
+			return err
+		}
+	}
+
+	*r = AlertRule{Name: rule.Name, Expr: rule.Expr, For: duration, Containers: rule.Containers, Severity: rule.Severity}
+	return nil
+}
+
+type Alert struct {
+	Rule        string     `json:"rule"`
+	Expr        string     `json:"expr"`
+	Severity    string     `json:"severity,omitempty"`
+	Subject     string     `json:"subject"`
+	ContainerID string     `json:"containerId,omitempty"`
+	Engine      string     `json:"engine,omitempty"`
+	State       string     `json:"state"`
+	Value       string     `json:"value"`
+	ActiveSince time.Time  `json:"activeSince"`
+	FiredAt     *time.Time `json:"firedAt,omitempty"`
+	ResolvedAt  *time.Time `json:"resolvedAt,omitempty"`
+}
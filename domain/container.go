@@ -6,4 +6,6 @@ type Container struct {
 	State       string   `json:"state"`
 	Status      string   `json:"status"`
 	PublicPorts []uint16 `json:"publicPorts"`
+	Engine      string   `json:"engine,omitempty"`
+	Node        string   `json:"node,omitempty"`
 }
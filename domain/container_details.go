@@ -0,0 +1,88 @@
+package domain
+
+import "time"
+
+type ContainerDetails struct {
+	Container
+	Image         string             `json:"image"`
+	Command       []string           `json:"command"`
+	Created       time.Time          `json:"created"`
+	StartedAt     *time.Time         `json:"startedAt,omitempty"`
+	FinishedAt    *time.Time         `json:"finishedAt,omitempty"`
+	ExitCode      int                `json:"exitCode"`
+	RestartCount  int                `json:"restartCount"`
+	RestartPolicy RestartPolicy      `json:"restartPolicy"`
+	Labels        map[string]string  `json:"labels"`
+	Env           []string           `json:"env"`
+	Networks      []ContainerNetwork `json:"networks"`
+	Ports         []PortMapping      `json:"ports"`
+	Mounts        []Mount            `json:"mounts"`
+	Resources     ResourceLimits     `json:"resources"`
+	Health        *Health            `json:"health,omitempty"`
+}
+
+type RestartPolicy struct {
+	Name              string `json:"name"`
+	MaximumRetryCount int    `json:"maximumRetryCount"`
+}
+
+type ContainerNetwork struct {
+	Name        string `json:"name"`
+	IPAddress   string `json:"ipAddress"`
+	IPv6Address string `json:"ipv6Address,omitempty"`
+	Gateway     string `json:"gateway"`
+	MacAddress  string `json:"macAddress"`
+}
+
+type PortMapping struct {
+	PrivatePort uint16 `json:"privatePort"`
+	PublicPort  uint16 `json:"publicPort,omitempty"`
+	Protocol    string `json:"protocol"`
+	HostIP      string `json:"hostIp,omitempty"`
+}
+
+type Mount struct {
+	Type        string `json:"type"`
+	Name        string `json:"name,omitempty"`
+	Source      string `json:"source"`
+	Destination string `json:"destination"`
+	Mode        string `json:"mode"`
+	ReadOnly    bool   `json:"readOnly"`
+}
+
+//ResourceLimits holds limits of a container, zero means unlimited
+type ResourceLimits struct {
+	NanoCpus   int64  `json:"nanoCpus"`
+	CpuShares  int64  `json:"cpuShares"`
+	CpuQuota   int64  `json:"cpuQuota"`
+	CpuPeriod  int64  `json:"cpuPeriod"`
+	CpusetCpus string `json:"cpusetCpus,omitempty"`
+	Memory     int64  `json:"memory"`
+	MemorySwap int64  `json:"memorySwap"`
+	PidsLimit  int64  `json:"pidsLimit"`
+}
+
+//defaultCpuPeriod is the CFS period docker uses when a quota is set without a period
+const defaultCpuPeriod = 100000
+
+//Cpus returns the number of CPUs the container may use, zero when it is unlimited
+func (l ResourceLimits) Cpus() float64 {
+	switch {
+	case l.NanoCpus > 0:
+		return float64(l.NanoCpus) / 1e9
+	case l.CpuQuota > 0:
+		period := l.CpuPeriod
+		if period <= 0 {
+			period = defaultCpuPeriod
+		}
+		return float64(l.CpuQuota) / float64(period)
+	default:
+		return 0
+	}
+}
+
+type Health struct {
+	Status        string `json:"status"`
+	FailingStreak int    `json:"failingStreak"`
+	LastOutput    string `json:"lastOutput,omitempty"`
+}
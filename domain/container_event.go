@@ -0,0 +1,14 @@
+package domain
+
+import "time"
+
+type ContainerEvent struct {
+	Time        time.Time `json:"time"`
+	ContainerID string    `json:"containerId"`
+	Name        string    `json:"name"`
+	Image       string    `json:"image"`
+	Action      string    `json:"action"`
+	ExitCode    *int      `json:"exitCode,omitempty"`
+	Health      string    `json:"health,omitempty"`
+	Engine      string    `json:"engine,omitempty"`
+}
@@ -0,0 +1,17 @@
+package domain
+
+import "time"
+
+type LogOptions struct {
+	Tail       string
+	Since      string
+	Until      string
+	Timestamps bool
+	Follow     bool
+}
+
+type LogEntry struct {
+	Stream    string     `json:"stream"`
+	Timestamp *time.Time `json:"timestamp,omitempty"`
+	Line      string     `json:"line"`
+}
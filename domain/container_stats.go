@@ -1,9 +1,71 @@
 package domain
 
+//ContainerStats holds resource usage of a container,
+//RxBytes and TxBytes are totals of all network interfaces listed in Networks,
+//UsedMemory excludes inactive page cache and MemoryUsage is its percentage of the memory limit,
+//CpuUsage is a percentage where 100 is one fully used core
 type ContainerStats struct {
-	RxBytes     int64
-	TxBytes     int64
-	UsedMemory  int64
-	MemoryUsage float32
-	CpuUsage    float32
+	RxBytes     int64          `json:"rxBytes"`
+	TxBytes     int64          `json:"txBytes"`
+	UsedMemory  int64          `json:"usedMemory"`
+	MemoryUsage float32        `json:"memoryUsage"`
+	CpuUsage    float32        `json:"cpuUsage"`
+	Cpu         CpuStats       `json:"cpu"`
+	Memory      MemoryStats    `json:"memory"`
+	Networks    []NetworkStats `json:"networks"`
+	BlockIO     BlockIOStats   `json:"blockIO"`
+	Pids        PidsStats      `json:"pids"`
+}
+
+//CpuStats breaks down CPU usage of a container in percents where 100 is one fully used core,
+//QuotaUsage is the percentage of the CPU quota, or of all online CPUs when the container is unlimited,
+//PerCore is empty on cgroup v2 hosts which do not report usage per core,
+//throttling counters are totals since the container started
+type CpuStats struct {
+	PerCore          []float32 `json:"perCore"`
+	User             float32   `json:"user"`
+	Kernel           float32   `json:"kernel"`
+	OnlineCpus       int       `json:"onlineCpus"`
+	Quota            float64   `json:"quota"`
+	QuotaUsage       float32   `json:"quotaUsage"`
+	Periods          int64     `json:"periods"`
+	ThrottledPeriods int64     `json:"throttledPeriods"`
+	ThrottledTime    int64     `json:"throttledTime"`
+}
+
+//MemoryStats breaks down memory of a container, Limit is zero when the container is unlimited,
+//swap is not reported by cgroup v2 hosts and stays zero there
+type MemoryStats struct {
+	RSS   int64 `json:"rss"`
+	Cache int64 `json:"cache"`
+	Swap  int64 `json:"swap"`
+	Limit int64 `json:"limit"`
+}
+
+//NetworkStats holds traffic counters of a network interface of a container
+type NetworkStats struct {
+	Interface string `json:"interface"`
+	RxBytes   int64  `json:"rxBytes"`
+	RxPackets int64  `json:"rxPackets"`
+	RxErrors  int64  `json:"rxErrors"`
+	RxDropped int64  `json:"rxDropped"`
+	TxBytes   int64  `json:"txBytes"`
+	TxPackets int64  `json:"txPackets"`
+	TxErrors  int64  `json:"txErrors"`
+	TxDropped int64  `json:"txDropped"`
+}
+
+//BlockIOStats holds block device counters of a container summed over all devices,
+//operations are not reported by cgroup v2 hosts and stay zero there
+type BlockIOStats struct {
+	ReadBytes  int64 `json:"readBytes"`
+	WriteBytes int64 `json:"writeBytes"`
+	ReadOps    int64 `json:"readOps"`
+	WriteOps   int64 `json:"writeOps"`
+}
+
+//PidsStats holds the number of processes and threads of a container, Limit is zero when unlimited
+type PidsStats struct {
+	Current int64 `json:"current"`
+	Limit   int64 `json:"limit"`
 }
@@ -0,0 +1,11 @@
+package domain
+
+//DockerEndpoint is a named docker daemon, Host is an unix://, tcp:// or ssh://user@host address,
+//TLS files are used by tcp endpoints and an empty Host uses DOCKER_HOST and related environment variables
+type DockerEndpoint struct {
+	Name    string `json:"name" yaml:"name"`
+	Host    string `json:"host" yaml:"host"`
+	TLSCA   string `json:"tlsCA,omitempty" yaml:"tlsCA"`
+	TLSCert string `json:"tlsCert,omitempty" yaml:"tlsCert"`
+	TLSKey  string `json:"tlsKey,omitempty" yaml:"tlsKey"`
+}
@@ -1,6 +1,9 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 //go:generate mockgen -package $GOPACKAGE -source $GOFILE -destination mock_$GOFILE
 
@@ -8,7 +11,20 @@ import "context"
 // Expect implementation by the infrastructure layer
 type DockerService interface {
 	GetContainer(ctx context.Context, idOrName string) (*Container, error)
+	InspectContainer(ctx context.Context, idOrName string) (*ContainerDetails, error)
 	GetContainers(ctx context.Context, all bool) (*[]Container, error)
-	GetContainerStats(ctx context.Context, containerId string, stream bool) (*ContainerStats, error)
+	GetContainerStats(ctx context.Context, containerId string) (*ContainerStats, error)
+	StreamContainerStats(ctx context.Context, containerId string) (<-chan ContainerStats, error)
+	GetContainerLogs(ctx context.Context, idOrName string, options LogOptions) (<-chan LogEntry, error)
 	GetVolumes(ctx context.Context) (*[]Volume, error)
+	WatchEvents(ctx context.Context, since time.Time) (<-chan ContainerEvent, <-chan error)
+	Status() EngineStatus
+	Ping(ctx context.Context) error
+	StartContainer(ctx context.Context, idOrName string) error
+	StopContainer(ctx context.Context, idOrName string, timeout *time.Duration) error
+	RestartContainer(ctx context.Context, idOrName string, timeout *time.Duration) error
+	PauseContainer(ctx context.Context, idOrName string) error
+	UnpauseContainer(ctx context.Context, idOrName string) error
+	KillContainer(ctx context.Context, idOrName string, signal string) error
+	RemoveContainer(ctx context.Context, idOrName string, force bool, removeVolumes bool) error
 }
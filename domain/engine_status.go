@@ -0,0 +1,16 @@
+package domain
+
+import "time"
+
+//EngineStatus is the connection state of a docker engine,
+//Since is when the engine got connected or disconnected and Error is the last failure while disconnected
+type EngineStatus struct {
+	Name          string    `json:"name"`
+	Host          string    `json:"host"`
+	Connected     bool      `json:"connected"`
+	Since         time.Time `json:"since"`
+	LastCheck     time.Time `json:"lastCheck"`
+	APIVersion    string    `json:"apiVersion,omitempty"`
+	ServerVersion string    `json:"serverVersion,omitempty"`
+	Error         string    `json:"error,omitempty"`
+}
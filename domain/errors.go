@@ -0,0 +1,40 @@
+package domain
+
+import "errors"
+
+//Kinds of errors, match them with errors.Is
+var (
+	ErrNotFound        = errors.New("not found")
+	ErrUnavailable     = errors.New("unavailable")
+	ErrForbidden       = errors.New("forbidden")
+	ErrInvalidArgument = errors.New("invalid argument")
+	ErrTimeout         = errors.New("timeout")
+	ErrConflict        = errors.New("conflict")
+)
+
+//Error classifies an underlying error by one of the kinds while keeping its message
+type Error struct {
+	Kind error
+	Err  error
+}
+
+//NewError classifies the error, nil stays nil
+func NewError(kind error, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &Error{Kind: kind, Err: err}
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func (e *Error) Is(target error) bool {
+	return target == e.Kind
+}
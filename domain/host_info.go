@@ -1,11 +1,11 @@
 package domain
 
 type HostInfo struct {
-	CpuUsage        float64
-	UsedSwapMemory  uint64
-	TotalSwapMemory uint64
-	UsedMemory      uint64
-	TotalMemory     uint64
-	UsedStorage     uint64
-	TotalStorage    uint64
+	CpuUsage        float64 `json:"cpuUsage"`
+	UsedSwapMemory  uint64  `json:"usedSwapMemory"`
+	TotalSwapMemory uint64  `json:"totalSwapMemory"`
+	UsedMemory      uint64  `json:"usedMemory"`
+	TotalMemory     uint64  `json:"totalMemory"`
+	UsedStorage     uint64  `json:"usedStorage"`
+	TotalStorage    uint64  `json:"totalStorage"`
 }
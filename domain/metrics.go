@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+//MetricsBucket aggregates samples of a series over a period of time,
+//a single sample is a bucket with Count equal to 1
+type MetricsBucket struct {
+	Time  time.Time          `json:"time"`
+	Count int                `json:"count"`
+	Avg   map[string]float64 `json:"avg"`
+	Min   map[string]float64 `json:"min"`
+	Max   map[string]float64 `json:"max"`
+}
+
+//Merge adds samples of src into the bucket keeping weighted averages
+func (b *MetricsBucket) Merge(src MetricsBucket) {
+	total := float64(b.Count + src.Count)
+	if total == 0 {
+		return
+	}
+
+	if b.Avg == nil {
+		b.Avg = make(map[string]float64)
+		b.Min = make(map[string]float64)
+		b.Max = make(map[string]float64)
+	}
+
+	for name, avg := range src.Avg {
+		prev, ok := b.Avg[name]
+		if !ok {
+			b.Avg[name] = avg
+			b.Min[name] = src.Min[name]
+			b.Max[name] = src.Max[name]
+			continue
+		}
+
+		b.Avg[name] = (prev*float64(b.Count) + avg*float64(src.Count)) / total
+		b.Min[name] = math.Min(b.Min[name], src.Min[name])
+		b.Max[name] = math.Max(b.Max[name], src.Max[name])
+	}
+
+	b.Count += src.Count
+}
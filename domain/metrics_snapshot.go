@@ -0,0 +1,17 @@
+package domain
+
+import "time"
+
+//MetricsSnapshot holds the latest sampled statistics of the host and all containers of every engine,
+//Containers is nil when containers could not be listed and lacks containers of FailedEngines
+type MetricsSnapshot struct {
+	Time          time.Time           `json:"time"`
+	Host          *HostInfo           `json:"host"`
+	Containers    []ContainerSnapshot `json:"containers"`
+	FailedEngines []string            `json:"failedEngines,omitempty"`
+}
+
+type ContainerSnapshot struct {
+	Container Container       `json:"container"`
+	Stats     *ContainerStats `json:"stats,omitempty"`
+}
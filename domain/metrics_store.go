@@ -0,0 +1,22 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+//go:generate mockgen -package $GOPACKAGE -source $GOFILE -destination mock_$GOFILE
+
+// MetricsStore represents storage of sampled metrics series
+// Expect implementation by the infrastructure layer
+type MetricsStore interface {
+	Append(ctx context.Context, series string, bucket MetricsBucket) error
+	Range(ctx context.Context, series string, from time.Time, to time.Time) ([]MetricsBucket, error)
+}
+
+//RetentionTier keeps buckets of Resolution for Retention,
+//older buckets are rolled up into the next tier or dropped by the last one
+type RetentionTier struct {
+	Resolution time.Duration `json:"resolution" yaml:"resolution"`
+	Retention  time.Duration `json:"retention" yaml:"retention"`
+}
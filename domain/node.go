@@ -0,0 +1,27 @@
+package domain
+
+import "time"
+
+const (
+	NodeHealthy  = "healthy"
+	NodeDegraded = "degraded"
+	NodeStale    = "stale"
+)
+
+//Node is a host reporting its snapshots to the fleet server,
+//it is degraded when containers of any of its engines could not be listed and stale when it stopped reporting
+type Node struct {
+	Name       string    `json:"name"`
+	Address    string    `json:"address"`
+	Status     string    `json:"status"`
+	LastSeen   time.Time `json:"lastSeen"`
+	Containers int       `json:"containers"`
+	Running    int       `json:"running"`
+	Host       *HostInfo `json:"host"`
+}
+
+//NodeReport is pushed by an agent every collection interval
+type NodeReport struct {
+	Node     string          `json:"node"`
+	Snapshot MetricsSnapshot `json:"snapshot"`
+}
@@ -0,0 +1,41 @@
+package domain
+
+import "time"
+
+const (
+	GenericFormat = "generic"
+	SlackFormat   = "slack"
+	TeamsFormat   = "teams"
+
+	DeliverySucceeded = "delivered"
+	DeliveryFailed    = "failed"
+)
+
+//Receiver is a webhook which receives firing and resolved alerts and container events,
+//Events defaults to die and oom
+type Receiver struct {
+	Name         string   `json:"name" yaml:"name"`
+	URL          string   `json:"url" yaml:"url"`
+	Format       string   `json:"format" yaml:"format"`
+	Secret       string   `json:"-" yaml:"secret"`
+	Events       []string `json:"events,omitempty" yaml:"events"`
+	IgnoreAlerts bool     `json:"ignoreAlerts" yaml:"ignoreAlerts"`
+}
+
+type Notification struct {
+	Time  time.Time       `json:"time"`
+	Title string          `json:"title"`
+	Text  string          `json:"text"`
+	Alert *Alert          `json:"alert,omitempty"`
+	Event *ContainerEvent `json:"event,omitempty"`
+}
+
+type Delivery struct {
+	Time       time.Time `json:"time"`
+	Receiver   string    `json:"receiver"`
+	Title      string    `json:"title"`
+	Status     string    `json:"status"`
+	Attempts   int       `json:"attempts"`
+	StatusCode int       `json:"statusCode,omitempty"`
+	Error      string    `json:"error,omitempty"`
+}
@@ -0,0 +1,15 @@
+package domain
+
+//Principal is an authenticated caller of the API
+type Principal struct {
+	Name   string `json:"name"`
+	Method string `json:"method"`
+}
+
+//go:generate mockgen -package $GOPACKAGE -source $GOFILE -destination mock_$GOFILE
+
+// PasswordVerifier represents a store of user passwords
+// Expect implementation by the infrastructure layer
+type PasswordVerifier interface {
+	Verify(user string, password string) bool
+}
@@ -0,0 +1,11 @@
+package domain
+
+import "context"
+
+//go:generate mockgen -package $GOPACKAGE -source $GOFILE -destination mock_$GOFILE
+
+// WebhookSender represents delivery of HTTP webhooks
+// Expect implementation by the infrastructure layer
+type WebhookSender interface {
+	Post(ctx context.Context, url string, body []byte, headers map[string]string) (int, error)
+}
@@ -0,0 +1,257 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"godtop/domain"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	boltFileName      = "godtop.db"
+	compactionPeriod  = 5 * time.Minute
+	boltOpenTimeout   = time.Second
+	boltFileMode      = 0600
+	boltDirectoryMode = 0750
+)
+
+type boltStore struct {
+	db    *bolt.DB
+	tiers []domain.RetentionTier
+}
+
+//CreateBoltStore opens or creates the metrics data file in the directory
+func CreateBoltStore(directory string, tiers []domain.RetentionTier) (*boltStore, error) {
+	if len(tiers) == 0 {
+		return nil, errors.New("at least one retention tier is required")
+	}
+
+	if err := os.MkdirAll(directory, boltDirectoryMode); err != nil {
+		return nil, err
+	}
+
+	db, err := bolt.Open(filepath.Join(directory, boltFileName), boltFileMode, &bolt.Options{Timeout: boltOpenTimeout})
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for i := range tiers {
+			if _, err := tx.CreateBucketIfNotExists(tierName(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &boltStore{db: db, tiers: tiers}, nil
+}
+
+func (s *boltStore) Append(ctx context.Context, series string, bucket domain.MetricsBucket) error {
+	value, err := json.Marshal(bucket)
+	if err != nil {
+		return err
+	}
+
+	return s.db.Batch(func(tx *bolt.Tx) error {
+		seriesBucket, err := tx.Bucket(tierName(0)).CreateBucketIfNotExists([]byte(series))
+		if err != nil {
+			return err
+		}
+		return seriesBucket.Put(timeKey(bucket.Time), value)
+	})
+}
+
+func (s *boltStore) Range(ctx context.Context, series string, from time.Time, to time.Time) ([]domain.MetricsBucket, error) {
+	var result []domain.MetricsBucket
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		for i := range s.tiers {
+			seriesBucket := tx.Bucket(tierName(i)).Bucket([]byte(series))
+			if seriesBucket == nil {
+				continue
+			}
+
+			cursor := seriesBucket.Cursor()
+			max := timeKey(to)
+			for key, value := cursor.Seek(timeKey(from)); key != nil && bytes.Compare(key, max) <= 0; key, value = cursor.Next() {
+				var bucket domain.MetricsBucket
+				if err := json.Unmarshal(value, &bucket); err != nil {
+					return err
+				}
+				result = append(result, bucket)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Time.Before(result[j].Time)
+	})
+
+	return result, nil
+}
+
+//Run compacts the store periodically until the context is done
+func (s *boltStore) Run(ctx context.Context) {
+	ticker := time.NewTicker(compactionPeriod)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Compact(time.Now()); err != nil {
+			log.Printf("metrics store: compaction failed: %s", err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+//Compact rolls buckets which outlived their tier into the next one and drops expired buckets
+func (s *boltStore) Compact(now time.Time) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		for i, tier := range s.tiers {
+			deadline := timeKey(now.Add(-tier.Retention))
+
+			var next *bolt.Bucket
+			var resolution time.Duration
+			if i+1 < len(s.tiers) {
+				next = tx.Bucket(tierName(i + 1))
+				resolution = s.tiers[i+1].Resolution
+			}
+
+			err := forEachSeries(tx.Bucket(tierName(i)), func(series []byte, seriesBucket *bolt.Bucket) error {
+				expired, err := takeBefore(seriesBucket, deadline)
+				if err != nil || next == nil || len(expired) == 0 {
+					return err
+				}
+
+				nextSeries, err := next.CreateBucketIfNotExists(series)
+				if err != nil {
+					return err
+				}
+				return rollUp(nextSeries, expired, resolution)
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *boltStore) Close() error {
+	return s.db.Close()
+}
+
+//region Private Methods
+
+func tierName(i int) []byte {
+	return []byte(fmt.Sprintf("tier%d", i))
+}
+
+func timeKey(t time.Time) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, uint64(t.UnixNano()))
+	return key
+}
+
+//forEachSeries calls fn for every series of a tier and deletes series left empty
+func forEachSeries(tier *bolt.Bucket, fn func(series []byte, seriesBucket *bolt.Bucket) error) error {
+	var names [][]byte
+	err := tier.ForEach(func(name, _ []byte) error {
+		names = append(names, append([]byte(nil), name...))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		seriesBucket := tier.Bucket(name)
+		if err := fn(name, seriesBucket); err != nil {
+			return err
+		}
+
+		if key, _ := seriesBucket.Cursor().First(); key == nil {
+			if err := tier.DeleteBucket(name); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+//takeBefore removes and returns buckets with keys before the deadline
+func takeBefore(seriesBucket *bolt.Bucket, deadline []byte) ([]domain.MetricsBucket, error) {
+	var result []domain.MetricsBucket
+
+	cursor := seriesBucket.Cursor()
+	for key, value := cursor.First(); key != nil && bytes.Compare(key, deadline) < 0; key, value = cursor.First() {
+		var bucket domain.MetricsBucket
+		if err := json.Unmarshal(value, &bucket); err != nil {
+			return nil, err
+		}
+		result = append(result, bucket)
+
+		if err := cursor.Delete(); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+//rollUp merges buckets into existing buckets of resolution
+func rollUp(seriesBucket *bolt.Bucket, buckets []domain.MetricsBucket, resolution time.Duration) error {
+	rollups := make(map[int64]*domain.MetricsBucket)
+	for _, bucket := range buckets {
+		start := bucket.Time.Truncate(resolution)
+		rollup, ok := rollups[start.UnixNano()]
+		if !ok {
+			rollup = &domain.MetricsBucket{Time: start}
+			if value := seriesBucket.Get(timeKey(start)); value != nil {
+				if err := json.Unmarshal(value, rollup); err != nil {
+					return err
+				}
+			}
+			rollups[start.UnixNano()] = rollup
+		}
+		rollup.Merge(bucket)
+	}
+
+	for _, rollup := range rollups {
+		value, err := json.Marshal(rollup)
+		if err != nil {
+			return err
+		}
+		if err := seriesBucket.Put(timeKey(rollup.Time), value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+//endregion
@@ -0,0 +1,126 @@
+package infrastructure
+
+import (
+	"context"
+	"godtop/domain"
+	"math"
+	"testing"
+	"time"
+)
+
+//testRetention keeps raw samples for a minute, 10 second buckets for 5 minutes and minute buckets for 10 minutes
+var testRetention = []domain.RetentionTier{
+	{Resolution: 0, Retention: time.Minute},
+	{Resolution: 10 * time.Second, Retention: 5 * time.Minute},
+	{Resolution: time.Minute, Retention: 10 * time.Minute},
+}
+
+func sample(at time.Time, value float64) domain.MetricsBucket {
+	return domain.MetricsBucket{
+		Time:  at,
+		Count: 1,
+		Avg:   map[string]float64{"cpu": value},
+		Min:   map[string]float64{"cpu": value},
+		Max:   map[string]float64{"cpu": value},
+	}
+}
+
+//aggregate is the part of a bucket the tests compare
+type aggregate struct {
+	offset        time.Duration
+	count         int
+	avg, min, max float64
+}
+
+func expectRange(t *testing.T, store *boltStore, start time.Time, from time.Duration, to time.Duration, want []aggregate) {
+	t.Helper()
+
+	buckets, err := store.Range(context.Background(), "host", start.Add(from), start.Add(to))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := make([]aggregate, len(buckets))
+	for i, bucket := range buckets {
+		got[i] = aggregate{bucket.Time.Sub(start), bucket.Count, bucket.Avg["cpu"], bucket.Min["cpu"], bucket.Max["cpu"]}
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("range %s..%s = %+v, want %+v", from, to, got, want)
+	}
+	for i := range want {
+		//averages are weighted in floating point
+		if got[i].offset != want[i].offset || got[i].count != want[i].count || math.Abs(got[i].avg-want[i].avg) > 1e-9 ||
+			got[i].min != want[i].min || got[i].max != want[i].max {
+			t.Errorf("range %s..%s bucket %d = %+v, want %+v", from, to, i, got[i], want[i])
+		}
+	}
+}
+
+func TestBoltStoreCompact(t *testing.T) {
+	store, err := CreateBoltStore(t.TempDir(), testRetention)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	start := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 12; i++ {
+		if err := store.Append(context.Background(), "host", sample(start.Add(time.Duration(i)*5*time.Second), float64(i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	//samples older than 30 seconds roll into 10 second buckets, newer ones stay raw
+	if err := store.Compact(start.Add(90 * time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	expectRange(t, store, start, 0, time.Minute, []aggregate{
+		{0, 2, 0.5, 0, 1},
+		{10 * time.Second, 2, 2.5, 2, 3},
+		{20 * time.Second, 2, 4.5, 4, 5},
+		{30 * time.Second, 1, 6, 6, 6},
+		{35 * time.Second, 1, 7, 7, 7},
+		{40 * time.Second, 1, 8, 8, 8},
+		{45 * time.Second, 1, 9, 9, 9},
+		{50 * time.Second, 1, 10, 10, 10},
+		{55 * time.Second, 1, 11, 11, 11},
+	})
+	expectRange(t, store, start, 10*time.Second, 40*time.Second, []aggregate{
+		{10 * time.Second, 2, 2.5, 2, 3},
+		{20 * time.Second, 2, 4.5, 4, 5},
+		{30 * time.Second, 1, 6, 6, 6},
+		{35 * time.Second, 1, 7, 7, 7},
+		{40 * time.Second, 1, 8, 8, 8},
+	})
+
+	//a late sample merges into the existing 10 second bucket
+	if err := store.Append(context.Background(), "host", sample(start.Add(8*time.Second), 20)); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Compact(start.Add(2 * time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	expectRange(t, store, start, 0, time.Minute, []aggregate{
+		{0, 3, 7, 0, 20},
+		{10 * time.Second, 2, 2.5, 2, 3},
+		{20 * time.Second, 2, 4.5, 4, 5},
+		{30 * time.Second, 2, 6.5, 6, 7},
+		{40 * time.Second, 2, 8.5, 8, 9},
+		{50 * time.Second, 2, 10.5, 10, 11},
+	})
+
+	//10 second buckets older than 5 minutes roll into a single minute bucket
+	if err := store.Compact(start.Add(7 * time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	expectRange(t, store, start, 0, time.Minute, []aggregate{
+		{0, 13, 86.0 / 13, 0, 20},
+	})
+
+	//the last tier drops expired buckets
+	if err := store.Compact(start.Add(20 * time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	expectRange(t, store, start, 0, time.Hour, nil)
+}
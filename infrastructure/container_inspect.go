@@ -0,0 +1,206 @@
+package infrastructure
+
+import (
+	"fmt"
+	"godtop/domain"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/go-connections/nat"
+	"github.com/docker/go-units"
+)
+
+//region Inspect Mapping
+
+func getContainerDetails(container types.ContainerJSON) *domain.ContainerDetails {
+	result := domain.ContainerDetails{
+		Container: domain.Container{
+			ID:    container.ID,
+			Names: getTrimmedNames([]string{container.Name}),
+		},
+		Command:      append([]string{container.Path}, container.Args...),
+		Created:      parseDockerTime(container.Created),
+		RestartCount: container.RestartCount,
+		Image:        container.Image,
+	}
+
+	if container.Config != nil {
+		result.Image = container.Config.Image
+		result.Labels = container.Config.Labels
+		result.Env = container.Config.Env
+	}
+
+	if state := container.State; state != nil {
+		result.State = state.Status
+		result.Status = getStatus(state)
+		result.ExitCode = state.ExitCode
+		result.StartedAt = parseOptionalDockerTime(state.StartedAt)
+		result.FinishedAt = parseOptionalDockerTime(state.FinishedAt)
+		result.Health = getHealth(state.Health)
+	}
+
+	if hostConfig := container.HostConfig; hostConfig != nil {
+		result.RestartPolicy = domain.RestartPolicy{
+			Name:              hostConfig.RestartPolicy.Name,
+			MaximumRetryCount: hostConfig.RestartPolicy.MaximumRetryCount,
+		}
+		result.Resources = domain.ResourceLimits{
+			NanoCpus:   hostConfig.NanoCPUs,
+			CpuShares:  hostConfig.CPUShares,
+			CpuQuota:   hostConfig.CPUQuota,
+			CpuPeriod:  hostConfig.CPUPeriod,
+			CpusetCpus: hostConfig.CpusetCpus,
+			Memory:     hostConfig.Memory,
+			MemorySwap: hostConfig.MemorySwap,
+		}
+		if hostConfig.PidsLimit != nil {
+			result.Resources.PidsLimit = *hostConfig.PidsLimit
+		}
+	}
+
+	if settings := container.NetworkSettings; settings != nil {
+		result.Networks = getNetworks(settings)
+		result.Ports = getPortMappings(settings.Ports)
+		for _, port := range result.Ports {
+			if port.PublicPort != 0 {
+				result.PublicPorts = append(result.PublicPorts, port.PublicPort)
+			}
+		}
+	}
+
+	for _, mount := range container.Mounts {
+		result.Mounts = append(result.Mounts, domain.Mount{
+			Type:        string(mount.Type),
+			Name:        mount.Name,
+			Source:      mount.Source,
+			Destination: mount.Destination,
+			Mode:        mount.Mode,
+			ReadOnly:    !mount.RW,
+		})
+	}
+
+	return &result
+}
+
+//getStatus returns human readable status like docker ps does
+func getStatus(state *types.ContainerState) string {
+	startedAt := parseDockerTime(state.StartedAt)
+	finishedAt := parseDockerTime(state.FinishedAt)
+
+	switch {
+	case state.Running && state.Paused:
+		return fmt.Sprintf("Up %s (Paused)", units.HumanDuration(time.Since(startedAt)))
+	case state.Restarting:
+		return fmt.Sprintf("Restarting (%d) %s ago", state.ExitCode, units.HumanDuration(time.Since(finishedAt)))
+	case state.Running:
+		status := "Up " + units.HumanDuration(time.Since(startedAt))
+		if state.Health != nil && state.Health.Status != types.NoHealthcheck {
+			status += " (" + state.Health.Status + ")"
+		}
+		return status
+	case state.Status == "removing":
+		return "Removal In Progress"
+	case state.Dead:
+		return "Dead"
+	case startedAt.IsZero():
+		return "Created"
+	case finishedAt.IsZero():
+		return ""
+	default:
+		return fmt.Sprintf("Exited (%d) %s ago", state.ExitCode, units.HumanDuration(time.Since(finishedAt)))
+	}
+}
+
+func getHealth(health *types.Health) *domain.Health {
+	if health == nil || health.Status == types.NoHealthcheck {
+		return nil
+	}
+
+	result := domain.Health{
+		Status:        health.Status,
+		FailingStreak: health.FailingStreak,
+	}
+	if len(health.Log) > 0 {
+		result.LastOutput = strings.TrimSpace(health.Log[len(health.Log)-1].Output)
+	}
+
+	return &result
+}
+
+func getNetworks(settings *types.NetworkSettings) []domain.ContainerNetwork {
+	var result []domain.ContainerNetwork
+	for name, endpoint := range settings.Networks {
+		if endpoint == nil {
+			continue
+		}
+
+		result = append(result, domain.ContainerNetwork{
+			Name:        name,
+			IPAddress:   endpoint.IPAddress,
+			IPv6Address: endpoint.GlobalIPv6Address,
+			Gateway:     endpoint.Gateway,
+			MacAddress:  endpoint.MacAddress,
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	return result
+}
+
+func getPortMappings(ports nat.PortMap) []domain.PortMapping {
+	var result []domain.PortMapping
+	for port, bindings := range ports {
+		mapping := domain.PortMapping{
+			PrivatePort: uint16(port.Int()),
+			Protocol:    port.Proto(),
+		}
+
+		if len(bindings) == 0 {
+			result = append(result, mapping)
+			continue
+		}
+
+		for _, binding := range bindings {
+			publicPort, _ := strconv.ParseUint(binding.HostPort, 10, 16)
+			mapping.PublicPort = uint16(publicPort)
+			mapping.HostIP = binding.HostIP
+			result = append(result, mapping)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].PrivatePort != result[j].PrivatePort {
+			return result[i].PrivatePort < result[j].PrivatePort
+		}
+		return result[i].HostIP < result[j].HostIP
+	})
+
+	return result
+}
+
+//parseDockerTime returns zero time for empty or unset docker timestamps
+func parseDockerTime(value string) time.Time {
+	result, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil || result.Year() <= 1 {
+		return time.Time{}
+	}
+
+	return result
+}
+
+func parseOptionalDockerTime(value string) *time.Time {
+	result := parseDockerTime(value)
+	if result.IsZero() {
+		return nil
+	}
+
+	return &result
+}
+
+//endregion
@@ -0,0 +1,132 @@
+package infrastructure
+
+import (
+	"context"
+	"godtop/domain"
+	"log"
+	"time"
+
+	"github.com/docker/docker/client"
+)
+
+const (
+	pingInterval        = 10 * time.Second
+	pingTimeout         = 5 * time.Second
+	minReconnectBackoff = time.Second
+	maxReconnectBackoff = 30 * time.Second
+)
+
+//client returns the shared client of the engine
+func (d *dockerEngine) client() *client.Client {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	return d.cli
+}
+
+//Status returns the connection state of the engine
+func (d *dockerEngine) Status() domain.EngineStatus {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	return d.status
+}
+
+//Ping checks that the daemon responds
+func (d *dockerEngine) Ping(ctx context.Context) error {
+	_, err := d.client().Ping(ctx)
+	return dockerError(err)
+}
+
+//Run pings the daemon every interval and reconnects with backoff while it is unavailable,
+//the client is closed when the context is done
+func (d *dockerEngine) Run(ctx context.Context) {
+	defer d.Close()
+
+	backoff := minReconnectBackoff
+	for {
+		wait := pingInterval
+		if err := d.check(ctx); err != nil {
+			wait = backoff
+			if backoff *= 2; backoff > maxReconnectBackoff {
+				backoff = maxReconnectBackoff
+			}
+		} else {
+			backoff = minReconnectBackoff
+		}
+
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+//Close releases connections of the shared client
+func (d *dockerEngine) Close() error {
+	return d.client().Close()
+}
+
+//check pings a connected daemon, a disconnected one is connected by a new client
+//so the API version is negotiated again with the daemon which may have been upgraded meanwhile
+func (d *dockerEngine) check(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, pingTimeout)
+	defer cancel()
+
+	if d.Status().Connected {
+		_, err := d.client().Ping(ctx)
+		d.setChecked(err)
+		return err
+	}
+
+	cli, err := client.NewClientWithOpts(d.options...)
+	if err != nil {
+		d.setChecked(err)
+		return err
+	}
+
+	cli.NegotiateAPIVersion(ctx)
+	version, err := cli.ServerVersion(ctx)
+	if err != nil {
+		cli.Close()
+		d.setChecked(err)
+		return err
+	}
+
+	d.mu.Lock()
+	previous := d.cli
+	d.cli = cli
+	d.status.Connected = true
+	d.status.Since = time.Now()
+	d.status.LastCheck = d.status.Since
+	d.status.APIVersion = cli.ClientVersion()
+	d.status.ServerVersion = version.Version
+	d.status.Error = ""
+	d.mu.Unlock()
+
+	previous.Close()
+	log.Printf("docker: engine %s connected to %s, docker %s, API %s", d.name, d.host, version.Version, cli.ClientVersion())
+
+	return nil
+}
+
+//setChecked records the result of a check logging when the engine gets unavailable
+func (d *dockerEngine) setChecked(err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := time.Now()
+	firstCheck := d.status.LastCheck.IsZero()
+	d.status.LastCheck = now
+	if err == nil {
+		return
+	}
+
+	if d.status.Connected || firstCheck {
+		log.Printf("docker: engine %s at %s is unavailable, reconnecting: %s", d.name, d.host, err)
+		d.status.Since = now
+	}
+	d.status.Connected = false
+	d.status.Error = err.Error()
+}
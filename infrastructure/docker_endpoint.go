@@ -0,0 +1,195 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"godtop/domain"
+	"io"
+	"net"
+	"net/url"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/client"
+)
+
+const (
+	//sshClientHost is a placeholder host of requests sent over the ssh connection
+	sshClientHost   = "http://docker.example.com"
+	maxStderrLength = 4096
+)
+
+//getClientOptions returns docker client options of the endpoint
+func getClientOptions(endpoint domain.DockerEndpoint) ([]client.Opt, error) {
+	if endpoint.Host == "" {
+		return []client.Opt{client.FromEnv, client.WithAPIVersionNegotiation()}, nil
+	}
+
+	hostURL, err := url.Parse(endpoint.Host)
+	if err != nil {
+		return nil, err
+	}
+
+	if hostURL.Scheme == "ssh" {
+		dialer, err := sshDialer(hostURL)
+		if err != nil {
+			return nil, err
+		}
+
+		return []client.Opt{
+			client.WithHost(sshClientHost),
+			client.WithDialContext(dialer),
+			client.WithAPIVersionNegotiation(),
+		}, nil
+	}
+
+	options := []client.Opt{client.WithHost(endpoint.Host), client.WithAPIVersionNegotiation()}
+	if endpoint.TLSCA != "" || endpoint.TLSCert != "" || endpoint.TLSKey != "" {
+		options = append(options, client.WithTLSClientConfig(endpoint.TLSCA, endpoint.TLSCert, endpoint.TLSKey))
+	}
+
+	return options, nil
+}
+
+//sshDialer connects to the remote daemon by running docker system dial-stdio over ssh,
+//the same way the docker CLI does, so ssh keys and agent of the user running godtop are used,
+//batch mode fails instead of waiting for a password prompt nobody answers
+func sshDialer(hostURL *url.URL) (func(ctx context.Context, network, addr string) (net.Conn, error), error) {
+	if hostURL.Hostname() == "" {
+		return nil, errors.New("ssh host is required")
+	}
+	if hostURL.Path != "" && hostURL.Path != "/" {
+		return nil, fmt.Errorf("ssh host must not have a path, got %q", hostURL.Path)
+	}
+
+	args := []string{"-o", "BatchMode=yes"}
+	if hostURL.User != nil {
+		args = append(args, "-l", hostURL.User.Username())
+	}
+	if port := hostURL.Port(); port != "" {
+		args = append(args, "-p", port)
+	}
+	args = append(args, "--", hostURL.Hostname(), "docker", "system", "dial-stdio")
+
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return dialCommand("ssh", args...)
+	}, nil
+}
+
+//commandConn is a connection over the standard input and output of a command
+type commandConn struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout io.ReadCloser
+	stderr *limitedBuffer
+
+	closeOnce sync.Once
+}
+
+//dialCommand starts the command which lives until the connection is closed, the dial context does not stop it
+func dialCommand(name string, args ...string) (net.Conn, error) {
+	cmd := exec.Command(name, args...)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	stderr := &limitedBuffer{}
+	cmd.Stderr = stderr
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	return &commandConn{cmd: cmd, stdin: stdin, stdout: stdout, stderr: stderr}, nil
+}
+
+func (c *commandConn) Read(p []byte) (int, error) {
+	n, err := c.stdout.Read(p)
+	if err == io.EOF {
+		if message := c.stderr.String(); message != "" {
+			return n, fmt.Errorf("%s: %s", c.cmd.Path, strings.TrimSpace(message))
+		}
+	}
+
+	return n, err
+}
+
+func (c *commandConn) Write(p []byte) (int, error) {
+	return c.stdin.Write(p)
+}
+
+func (c *commandConn) Close() error {
+	c.closeOnce.Do(func() {
+		c.stdin.Close()
+		c.cmd.Process.Kill()
+		c.cmd.Wait()
+	})
+
+	return nil
+}
+
+func (c *commandConn) LocalAddr() net.Addr {
+	return commandAddr{}
+}
+
+func (c *commandConn) RemoteAddr() net.Addr {
+	return commandAddr{}
+}
+
+//SetDeadline is not supported by pipes, requests are bounded by their contexts instead
+func (c *commandConn) SetDeadline(t time.Time) error {
+	return nil
+}
+
+func (c *commandConn) SetReadDeadline(t time.Time) error {
+	return nil
+}
+
+func (c *commandConn) SetWriteDeadline(t time.Time) error {
+	return nil
+}
+
+type commandAddr struct{}
+
+func (commandAddr) Network() string {
+	return "command"
+}
+
+func (commandAddr) String() string {
+	return "command"
+}
+
+//limitedBuffer keeps the beginning of the command error output
+type limitedBuffer struct {
+	mu      sync.Mutex
+	content []byte
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if free := maxStderrLength - len(b.content); free > 0 {
+		if len(p) > free {
+			b.content = append(b.content, p[:free]...)
+		} else {
+			b.content = append(b.content, p...)
+		}
+	}
+
+	return len(p), nil
+}
+
+func (b *limitedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return string(b.content)
+}
@@ -0,0 +1,34 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"godtop/domain"
+	"net/url"
+	"os"
+
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/errdefs"
+)
+
+//dockerError classifies errors of the docker client by domain error kinds, other errors are returned as they are
+func dockerError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errdefs.IsNotFound(err):
+		return domain.NewError(domain.ErrNotFound, err)
+	case errdefs.IsInvalidParameter(err):
+		return domain.NewError(domain.ErrInvalidArgument, err)
+	case errdefs.IsConflict(err), errdefs.IsNotModified(err):
+		return domain.NewError(domain.ErrConflict, err)
+	case errdefs.IsForbidden(err), errdefs.IsUnauthorized(err), errors.Is(err, os.ErrPermission):
+		return domain.NewError(domain.ErrForbidden, err)
+	case errdefs.IsDeadline(err), errors.Is(err, context.DeadlineExceeded):
+		return domain.NewError(domain.ErrTimeout, err)
+	case errdefs.IsUnavailable(err), client.IsErrConnectionFailed(err), errors.As(err, new(*url.Error)):
+		return domain.NewError(domain.ErrUnavailable, err)
+	}
+
+	return err
+}
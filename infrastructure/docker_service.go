@@ -3,37 +3,95 @@ package infrastructure
 import (
 	"bytes"
 	"context"
-	"errors"
+	"encoding/json"
+	"fmt"
 	"godtop/domain"
 	"io"
+	"math"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/client"
 	"github.com/tidwall/gjson"
 )
 
+//unlimitedMemory is the lowest limit treated as unlimited, cgroup v1 reports unlimited memory as the page counter maximum
+const unlimitedMemory = 1 << 62
+
+//quotaTTL is how long CPU quotas of containers are cached, limits rarely change while a container runs
+const quotaTTL = time.Minute
+
+//containerEventActions are lifecycle actions watched by WatchEvents
+var containerEventActions = []string{"create", "start", "die", "oom", "health_status", "restart", "destroy"}
+
+//dockerEngine shares one client between all requests, the client is replaced when the daemon comes back after an outage
 type dockerEngine struct {
+	name    string
+	host    string
+	options []client.Opt
+
+	mu     sync.RWMutex
+	cli    *client.Client
+	status domain.EngineStatus
+
+	quotasMu sync.Mutex
+	quotas   map[string]cachedQuota
 }
 
-func CreateDockerService() *dockerEngine {
-	return &dockerEngine{}
+type cachedQuota struct {
+	cpus    float64
+	expires time.Time
 }
 
-//GetAllContainers returns list of docker containers
-func (d dockerEngine) GetContainers(ctx context.Context, all bool) (*[]domain.Container, error) {
-	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+//CreateDockerService creates the client of the endpoint, Run keeps checking the connection
+func CreateDockerService(endpoint domain.DockerEndpoint) (*dockerEngine, error) {
+	options, err := getClientOptions(endpoint)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("engine %s: %s", endpoint.Name, err)
+	}
+
+	cli, err := client.NewClientWithOpts(options...)
+	if err != nil {
+		return nil, fmt.Errorf("engine %s: %s", endpoint.Name, err)
 	}
 
+	host := endpoint.Host
+	if host == "" {
+		host = cli.DaemonHost()
+	}
+
+	return &dockerEngine{
+		name:    endpoint.Name,
+		host:    host,
+		options: options,
+		cli:     cli,
+		quotas:  make(map[string]cachedQuota),
+		status: domain.EngineStatus{
+			Name:  endpoint.Name,
+			Host:  host,
+			Since: time.Now(),
+			Error: "not checked yet",
+		},
+	}, nil
+}
+
+//GetAllContainers returns list of docker containers
+func (d *dockerEngine) GetContainers(ctx context.Context, all bool) (*[]domain.Container, error) {
+	cli := d.client()
+
 	options := types.ContainerListOptions{
 		All: all,
 	}
 
 	containers, err := cli.ContainerList(ctx, options)
 	if err != nil {
-		return nil, err
+		return nil, dockerError(err)
 	}
 
 	result := make([]domain.Container, len(containers))
@@ -44,6 +102,7 @@ func (d dockerEngine) GetContainers(ctx context.Context, all bool) (*[]domain.Co
 			State:       container.State,
 			Status:      container.Status,
 			PublicPorts: getPublicPorts(container.Ports),
+			Engine:      d.name,
 		}
 	}
 
@@ -51,37 +110,40 @@ func (d dockerEngine) GetContainers(ctx context.Context, all bool) (*[]domain.Co
 }
 
 //GetContainer returns container by id or name even even not running
-func (d dockerEngine) GetContainer(ctx context.Context, idOrName string) (*domain.Container, error) {
-	containers, err := d.GetContainers(ctx, true)
+func (d *dockerEngine) GetContainer(ctx context.Context, idOrName string) (*domain.Container, error) {
+	details, err := d.InspectContainer(ctx, idOrName)
 	if err != nil {
 		return nil, err
 	}
 
-	for _, container := range *containers {
-		if container.ID == idOrName {
-			return &container, nil
-		}
+	return &details.Container, nil
+}
 
-		for _, name := range container.Names {
-			if strings.TrimPrefix(name, "/") == idOrName {
-				return &container, nil
-			}
-		}
+//InspectContainer returns detailed information about a container by id or name
+func (d *dockerEngine) InspectContainer(ctx context.Context, idOrName string) (*domain.ContainerDetails, error) {
+	cli := d.client()
+
+	container, err := cli.ContainerInspect(ctx, idOrName)
+	if err != nil {
+		return nil, dockerError(err)
 	}
 
-	return nil, errors.New("cannot find a container")
+	details := getContainerDetails(container)
+	details.Engine = d.name
+
+	return details, nil
 }
 
-func (d dockerEngine) GetContainerStats(ctx context.Context, containerId string, stream bool) (*domain.ContainerStats, error) {
-	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
-	if err != nil {
-		return nil, err
-	}
+//GetContainerStats returns a single statistics sample of a container, StreamContainerStats keeps sampling,
+//the daemon reads two samples a second apart so CPU usage is current rather than averaged over the container lifetime
+func (d *dockerEngine) GetContainerStats(ctx context.Context, containerId string) (*domain.ContainerStats, error) {
+	cli := d.client()
 
-	response, err := cli.ContainerStatsOneShot(ctx, containerId)
+	response, err := cli.ContainerStats(ctx, containerId, false)
 	if err != nil {
-		return nil, err
+		return nil, dockerError(err)
 	}
+	defer response.Body.Close()
 
 	var buff bytes.Buffer
 	_, err = io.Copy(&buff, response.Body)
@@ -90,23 +152,125 @@ func (d dockerEngine) GetContainerStats(ctx context.Context, containerId string,
 	}
 	jsonBytes := buff.Bytes()
 
-	result := domain.ContainerStats{}
-	result.RxBytes, result.TxBytes = getNetworkStats(&jsonBytes)
-	result.UsedMemory, result.MemoryUsage = getMemoryStats(&jsonBytes)
-	result.CpuUsage = getCpuStats(&jsonBytes)
+	return parseContainerStats(&jsonBytes, d.cpuQuota(ctx, containerId)), nil
+}
 
-	return &result, nil
+//cpuQuota returns the number of CPUs the container may use, cached for quotaTTL,
+//zero when it is unlimited or cannot be inspected so that usage is normalized to all CPUs
+func (d *dockerEngine) cpuQuota(ctx context.Context, containerId string) float64 {
+	now := time.Now()
+
+	d.quotasMu.Lock()
+	cached, ok := d.quotas[containerId]
+	d.quotasMu.Unlock()
+	if ok && now.Before(cached.expires) {
+		return cached.cpus
+	}
+
+	container, err := d.client().ContainerInspect(ctx, containerId)
+	if err != nil {
+		return 0
+	}
+	cpus := getContainerDetails(container).Resources.Cpus()
+
+	d.quotasMu.Lock()
+	defer d.quotasMu.Unlock()
+	for id, quota := range d.quotas {
+		if now.After(quota.expires) {
+			delete(d.quotas, id)
+		}
+	}
+	d.quotas[containerId] = cachedQuota{cpus: cpus, expires: now.Add(quotaTTL)}
+
+	return cpus
 }
 
-func (d dockerEngine) GetVolumes(ctx context.Context) (*[]domain.Volume, error) {
-	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+//StreamContainerStats keeps a stats stream open and sends every parsed frame until the context is done
+func (d *dockerEngine) StreamContainerStats(ctx context.Context, containerId string) (<-chan domain.ContainerStats, error) {
+	cli := d.client()
+
+	response, err := cli.ContainerStats(ctx, containerId, true)
 	if err != nil {
-		return nil, err
+		return nil, dockerError(err)
+	}
+
+	quota := d.cpuQuota(ctx, containerId)
+	result := make(chan domain.ContainerStats)
+	go func() {
+		defer close(result)
+		defer response.Body.Close()
+
+		decoder := json.NewDecoder(response.Body)
+		for {
+			var frame json.RawMessage
+			if err := decoder.Decode(&frame); err != nil {
+				return
+			}
+
+			jsonBytes := []byte(frame)
+			select {
+			case result <- *parseContainerStats(&jsonBytes, quota):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return result, nil
+}
+
+//GetContainerLogs returns log lines of a container tagged with their stream
+func (d *dockerEngine) GetContainerLogs(ctx context.Context, idOrName string, options domain.LogOptions) (<-chan domain.LogEntry, error) {
+	cli := d.client()
+
+	container, err := cli.ContainerInspect(ctx, idOrName)
+	if err != nil {
+		return nil, dockerError(err)
+	}
+
+	logsOptions := types.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Since:      options.Since,
+		Until:      options.Until,
+		Timestamps: options.Timestamps,
+		Follow:     options.Follow,
+		Tail:       options.Tail,
+	}
+
+	body, err := cli.ContainerLogs(ctx, container.ID, logsOptions)
+	if err != nil {
+		return nil, dockerError(err)
 	}
 
+	result := make(chan domain.LogEntry)
+	go func() {
+		defer close(result)
+		defer body.Close()
+
+		reader := logReader{
+			tty:        container.Config != nil && container.Config.Tty,
+			timestamps: options.Timestamps,
+		}
+		reader.read(body, func(entry domain.LogEntry) bool {
+			select {
+			case result <- entry:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		})
+	}()
+
+	return result, nil
+}
+
+func (d *dockerEngine) GetVolumes(ctx context.Context) (*[]domain.Volume, error) {
+	cli := d.client()
+
 	containers, err := cli.ContainerList(ctx, types.ContainerListOptions{})
 	if err != nil {
-		return nil, err
+		return nil, dockerError(err)
 	}
 
 	var volumes []domain.Volume
@@ -126,6 +290,94 @@ func (d dockerEngine) GetVolumes(ctx context.Context) (*[]domain.Volume, error)
 	return &volumes, nil
 }
 
+func (d *dockerEngine) StartContainer(ctx context.Context, idOrName string) error {
+	cli := d.client()
+
+	return dockerError(cli.ContainerStart(ctx, idOrName, types.ContainerStartOptions{}))
+}
+
+func (d *dockerEngine) StopContainer(ctx context.Context, idOrName string, timeout *time.Duration) error {
+	cli := d.client()
+
+	return dockerError(cli.ContainerStop(ctx, idOrName, timeout))
+}
+
+func (d *dockerEngine) RestartContainer(ctx context.Context, idOrName string, timeout *time.Duration) error {
+	cli := d.client()
+
+	return dockerError(cli.ContainerRestart(ctx, idOrName, timeout))
+}
+
+func (d *dockerEngine) PauseContainer(ctx context.Context, idOrName string) error {
+	cli := d.client()
+
+	return dockerError(cli.ContainerPause(ctx, idOrName))
+}
+
+func (d *dockerEngine) UnpauseContainer(ctx context.Context, idOrName string) error {
+	cli := d.client()
+
+	return dockerError(cli.ContainerUnpause(ctx, idOrName))
+}
+
+//KillContainer sends a signal to a container, SIGKILL when signal is empty
+func (d *dockerEngine) KillContainer(ctx context.Context, idOrName string, signal string) error {
+	cli := d.client()
+
+	return dockerError(cli.ContainerKill(ctx, idOrName, signal))
+}
+
+func (d *dockerEngine) RemoveContainer(ctx context.Context, idOrName string, force bool, removeVolumes bool) error {
+	cli := d.client()
+
+	options := types.ContainerRemoveOptions{
+		Force:         force,
+		RemoveVolumes: removeVolumes,
+	}
+
+	return dockerError(cli.ContainerRemove(ctx, idOrName, options))
+}
+
+//WatchEvents streams lifecycle events of containers since the time, zero time means from now on,
+//the error channel receives a single error when the stream ends
+func (d *dockerEngine) WatchEvents(ctx context.Context, since time.Time) (<-chan domain.ContainerEvent, <-chan error) {
+	result := make(chan domain.ContainerEvent)
+	errs := make(chan error, 1)
+
+	cli := d.client()
+
+	options := types.EventsOptions{
+		Filters: filters.NewArgs(filters.Arg("type", events.ContainerEventType)),
+	}
+	for _, action := range containerEventActions {
+		options.Filters.Add("event", action)
+	}
+	if !since.IsZero() {
+		options.Since = fmt.Sprintf("%d.%09d", since.Unix(), since.Nanosecond())
+	}
+
+	messages, messageErrs := cli.Events(ctx, options)
+	go func() {
+		defer close(result)
+		for {
+			select {
+			case message := <-messages:
+				select {
+				case result <- getContainerEvent(message):
+				case <-ctx.Done():
+					errs <- ctx.Err()
+					return
+				}
+			case err := <-messageErrs:
+				errs <- dockerError(err)
+				return
+			}
+		}
+	}()
+
+	return result, errs
+}
+
 //region Private Methods
 
 func getTrimmedNames(names []string) []string {
@@ -152,65 +404,210 @@ func getPublicPorts(ports []types.Port) []uint16 {
 	return result
 }
 
-func getNetworkStats(jsonBytes *[]byte) (rx int64, tx int64) {
-	eth0 := gjson.GetBytes(*jsonBytes, "networks.eth0")
-	if eth0.Type.String() != "Null" {
-		rx = eth0.Get("rx_bytes").Int()
-		tx = eth0.Get("tx_bytes").Int()
+//parseContainerStats parses a stats frame, quota is the number of CPUs the container may use or zero when it is unlimited
+func parseContainerStats(jsonBytes *[]byte, quota float64) *domain.ContainerStats {
+	result := domain.ContainerStats{}
+	result.Networks = getNetworkStats(jsonBytes)
+	for _, network := range result.Networks {
+		result.RxBytes += network.RxBytes
+		result.TxBytes += network.TxBytes
 	}
+	result.UsedMemory, result.MemoryUsage, result.Memory = getMemoryStats(jsonBytes)
+	result.CpuUsage, result.Cpu = getCpuStats(jsonBytes, quota)
+	result.BlockIO = getBlockIOStats(jsonBytes)
+	result.Pids = getPidsStats(jsonBytes)
 
-	return rx, tx
+	return &result
 }
 
-func getMemoryStats(jsonBytes *[]byte) (usedMemory int64, memoryUsage float32) {
-	//used_memory = memory_stats.usage - memory_stats.stats.cache
-	//available_memory = memory_stats.limit
-	//memory_usage% = (used_memory / available_memory) * 100.0
-	memory := gjson.GetBytes(*jsonBytes, "memory_stats")
-	if memory.Type.String() != "Null" {
-		usage := memory.Get("usage")
-		cache := memory.Get("stats.cache")
-		if usage.Type.String() != "Null" && cache.Type.String() != "Null" {
-			usedMemory = usage.Int() - cache.Int()
+func getContainerEvent(message events.Message) domain.ContainerEvent {
+	result := domain.ContainerEvent{
+		Time:        time.Unix(0, message.TimeNano),
+		ContainerID: message.Actor.ID,
+		Name:        message.Actor.Attributes["name"],
+		Image:       message.Actor.Attributes["image"],
+		Action:      message.Action,
+	}
+
+	//health events look like "health_status: healthy"
+	if index := strings.Index(message.Action, ":"); index >= 0 {
+		result.Action = message.Action[:index]
+		result.Health = strings.TrimSpace(message.Action[index+1:])
+	}
+
+	if exitCode, err := strconv.Atoi(message.Actor.Attributes["exitCode"]); err == nil {
+		result.ExitCode = &exitCode
+	}
+
+	return result
+}
+
+//getNetworkStats returns counters of every network interface ordered by interface name
+func getNetworkStats(jsonBytes *[]byte) []domain.NetworkStats {
+	result := []domain.NetworkStats{}
+	gjson.GetBytes(*jsonBytes, "networks").ForEach(func(name gjson.Result, network gjson.Result) bool {
+		result = append(result, domain.NetworkStats{
+			Interface: name.String(),
+			RxBytes:   network.Get("rx_bytes").Int(),
+			RxPackets: network.Get("rx_packets").Int(),
+			RxErrors:  network.Get("rx_errors").Int(),
+			RxDropped: network.Get("rx_dropped").Int(),
+			TxBytes:   network.Get("tx_bytes").Int(),
+			TxPackets: network.Get("tx_packets").Int(),
+			TxErrors:  network.Get("tx_errors").Int(),
+			TxDropped: network.Get("tx_dropped").Int(),
+		})
+		return true
+	})
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Interface < result[j].Interface
+	})
+
+	return result
+}
+
+func getBlockIOStats(jsonBytes *[]byte) domain.BlockIOStats {
+	//entries are reported per device and operation, cgroup v1 names operations Read and Write, cgroup v2 read and write
+	blkio := gjson.GetBytes(*jsonBytes, "blkio_stats")
+
+	result := domain.BlockIOStats{}
+	result.ReadBytes, result.WriteBytes = sumBlockIO(blkio.Get("io_service_bytes_recursive"))
+	result.ReadOps, result.WriteOps = sumBlockIO(blkio.Get("io_serviced_recursive"))
+
+	return result
+}
+
+func sumBlockIO(entries gjson.Result) (read int64, write int64) {
+	entries.ForEach(func(_ gjson.Result, entry gjson.Result) bool {
+		switch strings.ToLower(entry.Get("op").String()) {
+		case "read":
+			read += entry.Get("value").Int()
+		case "write":
+			write += entry.Get("value").Int()
 		}
+		return true
+	})
+
+	return read, write
+}
+
+func getPidsStats(jsonBytes *[]byte) domain.PidsStats {
+	pids := gjson.GetBytes(*jsonBytes, "pids_stats")
+
+	result := domain.PidsStats{
+		Current: pids.Get("current").Int(),
+	}
+	//unlimited is reported as the maximum unsigned value by some engines
+	if limit := pids.Get("limit").Uint(); limit <= math.MaxInt64 {
+		result.Limit = int64(limit)
+	}
+
+	return result
+}
+
+func getMemoryStats(jsonBytes *[]byte) (usedMemory int64, memoryUsage float32, details domain.MemoryStats) {
+	//used_memory = memory_stats.usage - inactive page cache, as docker stats computes it
+	//cgroup v1 reports the cache as stats.total_inactive_file (stats.cache on old engines), cgroup v2 as stats.inactive_file
+	//memory_usage% = (used_memory / memory_stats.limit) * 100.0, zero when the container is unlimited
+	memory := gjson.GetBytes(*jsonBytes, "memory_stats")
+	if !memory.Exists() {
+		return usedMemory, memoryUsage, details
+	}
 
-		available := memory.Get("limit")
-		if available.Type.String() != "Null" {
-			memoryUsage = (float32(usedMemory) / float32(available.Int())) * 100.0
+	usage := memory.Get("usage").Int()
+	stats := memory.Get("stats")
+
+	var inactive gjson.Result
+	if stats.Get("cache").Exists() || stats.Get("total_inactive_file").Exists() {
+		inactive = firstExisting(stats, "total_inactive_file", "cache")
+		details.RSS = firstExisting(stats, "total_rss", "rss").Int()
+		details.Cache = firstExisting(stats, "total_cache", "cache").Int()
+		details.Swap = firstExisting(stats, "total_swap", "swap").Int()
+	} else {
+		inactive = stats.Get("inactive_file")
+		details.RSS = stats.Get("anon").Int()
+		details.Cache = stats.Get("file").Int()
+	}
+
+	usedMemory = usage
+	if inactive.Int() < usage {
+		usedMemory = usage - inactive.Int()
+	}
+
+	if limit := memory.Get("limit").Uint(); limit > 0 && limit < unlimitedMemory {
+		details.Limit = int64(limit)
+		memoryUsage = (float32(usedMemory) / float32(limit)) * 100.0
+	}
+
+	return usedMemory, memoryUsage, details
+}
+
+//firstExisting returns the first of the fields which exists, cgroup v1 reports totals of the hierarchy with the total_ prefix
+func firstExisting(stats gjson.Result, fields ...string) gjson.Result {
+	for _, field := range fields {
+		if value := stats.Get(field); value.Exists() {
+			return value
 		}
 	}
 
-	return usedMemory, memoryUsage
+	return gjson.Result{}
 }
 
-func getCpuStats(jsonBytes *[]byte) float32 {
+func getCpuStats(jsonBytes *[]byte, quota float64) (cpuUsage float32, details domain.CpuStats) {
 	//cpu_delta = cpu_stats.cpu_usage.total_usage - precpu_stats.cpu_usage.total_usage
 	//system_cpu_delta = cpu_stats.system_cpu_usage - precpu_stats.system_cpu_usage
 	//number_cpus = cpu_stats.online_cpus (if older lenght(cpu_stats.cpu_usage.percpu_usage))
 	//cpu_usage% = (cpu_delta / system_cpu_delta) * number_cpus * 100.0
+	//usage is zero when precpu_stats has no system_cpu_usage, one-shot reads and first stream frames have no previous sample
+	//per core, user and kernel usage replace cpu_delta with deltas of percpu_usage, usage_in_usermode and usage_in_kernelmode
 	cpu := gjson.GetBytes(*jsonBytes, "cpu_stats")
 	precpu := gjson.GetBytes(*jsonBytes, "precpu_stats")
-	if cpu.Type.String() == "Null" || precpu.Type.String() == "Null" {
-		return 0
+
+	details.Quota = quota
+	details.Periods = cpu.Get("throttling_data.periods").Int()
+	details.ThrottledPeriods = cpu.Get("throttling_data.throttled_periods").Int()
+	details.ThrottledTime = cpu.Get("throttling_data.throttled_time").Int()
+
+	percpu := cpu.Get("cpu_usage.percpu_usage").Array()
+	details.OnlineCpus = int(cpu.Get("online_cpus").Int())
+	if details.OnlineCpus == 0 {
+		details.OnlineCpus = len(percpu)
 	}
 
-	totalUsage := cpu.Get("cpu_usage.total_usage")
-	preTotalUsage := precpu.Get("cpu_usage.total_usage")
-	if totalUsage.Type.String() == "Null" {
-		return 0
+	systemCpuDelta := cpu.Get("system_cpu_usage").Float() - precpu.Get("system_cpu_usage").Float()
+	if precpu.Get("system_cpu_usage").Float() == 0 || systemCpuDelta <= 0 || details.OnlineCpus == 0 {
+		return cpuUsage, details
 	}
 
-	cpuDelta := totalUsage.Int() - preTotalUsage.Int()
-	systemUsage := cpu.Get("system_cpu_usage")
-	preSystemUsage := precpu.Get("system_cpu_usage")
-	if systemUsage.Type.String() == "Null" {
-		return 0
+	percent := func(current gjson.Result, previous gjson.Result) float32 {
+		delta := current.Float() - previous.Float()
+		if delta <= 0 {
+			return 0
+		}
+		return float32(delta / systemCpuDelta * float64(details.OnlineCpus) * 100.0)
 	}
 
-	systemCpuDelta := systemUsage.Int() - preSystemUsage.Int()
-	number_cpus := cpu.Get("online_cpus")
+	cpuUsage = percent(cpu.Get("cpu_usage.total_usage"), precpu.Get("cpu_usage.total_usage"))
+	details.User = percent(cpu.Get("cpu_usage.usage_in_usermode"), precpu.Get("cpu_usage.usage_in_usermode"))
+	details.Kernel = percent(cpu.Get("cpu_usage.usage_in_kernelmode"), precpu.Get("cpu_usage.usage_in_kernelmode"))
+
+	prepercpu := precpu.Get("cpu_usage.percpu_usage").Array()
+	for i, core := range percpu {
+		var previous gjson.Result
+		if i < len(prepercpu) {
+			previous = prepercpu[i]
+		}
+		details.PerCore = append(details.PerCore, percent(core, previous))
+	}
+
+	if quota > 0 {
+		details.QuotaUsage = cpuUsage / float32(quota)
+	} else {
+		details.QuotaUsage = cpuUsage / float32(details.OnlineCpus)
+	}
 
-	return float32(cpuDelta/systemCpuDelta) * float32(number_cpus.Int()) * 100.0
+	return cpuUsage, details
 }
 
 //endregion
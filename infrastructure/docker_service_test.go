@@ -0,0 +1,195 @@
+package infrastructure
+
+import (
+	"godtop/domain"
+	"io/ioutil"
+	"math"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+//stats fixtures are synthetic frames shaped like the output of /containers/{id}/stats on cgroup v1 and v2 hosts,
+//of an old engine which reports usage per core but no online_cpus and of a one-shot read which has no previous CPU sample,
+//values are round to keep expectations readable
+const (
+	statsCgroupV1 = "stats_cgroup_v1.json"
+	statsCgroupV2 = "stats_cgroup_v2.json"
+	statsPercpu   = "stats_percpu.json"
+	statsOneShot  = "stats_one_shot.json"
+)
+
+func readStats(t *testing.T, fixture string) *[]byte {
+	t.Helper()
+
+	jsonBytes, err := ioutil.ReadFile(filepath.Join("testdata", fixture))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &jsonBytes
+}
+
+//round keeps two decimals so percentages computed in floating point compare equal
+func round(value float32) float32 {
+	return float32(math.Round(float64(value)*100) / 100)
+}
+
+func TestGetCpuStats(t *testing.T) {
+	tests := []struct {
+		fixture string
+		quota   float64
+		usage   float32
+		details domain.CpuStats
+	}{
+		{statsCgroupV1, 0.5, 20, domain.CpuStats{
+			PerCore:          []float32{10, 10},
+			User:             15,
+			Kernel:           5,
+			OnlineCpus:       2,
+			Quota:            0.5,
+			QuotaUsage:       40,
+			Periods:          120,
+			ThrottledPeriods: 7,
+			ThrottledTime:    35000000,
+		}},
+		{statsCgroupV2, 0, 200, domain.CpuStats{
+			User:       100,
+			Kernel:     100,
+			OnlineCpus: 4,
+			QuotaUsage: 50,
+		}},
+		{statsPercpu, 2, 40, domain.CpuStats{
+			PerCore:    []float32{20, 10, 10, 0},
+			User:       15,
+			Kernel:     5,
+			OnlineCpus: 4,
+			Quota:      2,
+			QuotaUsage: 20,
+		}},
+		{statsOneShot, 0, 0, domain.CpuStats{
+			OnlineCpus: 4,
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.fixture, func(t *testing.T) {
+			usage, details := getCpuStats(readStats(t, test.fixture), test.quota)
+
+			for i := range details.PerCore {
+				details.PerCore[i] = round(details.PerCore[i])
+			}
+			details.User, details.Kernel, details.QuotaUsage = round(details.User), round(details.Kernel), round(details.QuotaUsage)
+
+			if round(usage) != test.usage {
+				t.Errorf("usage = %v, want %v", usage, test.usage)
+			}
+			if !reflect.DeepEqual(details, test.details) {
+				t.Errorf("details = %+v, want %+v", details, test.details)
+			}
+		})
+	}
+}
+
+func TestGetMemoryStats(t *testing.T) {
+	tests := []struct {
+		fixture string
+		used    int64
+		usage   float32
+		details domain.MemoryStats
+	}{
+		{statsCgroupV1, 157286400, 30, domain.MemoryStats{RSS: 125829120, Cache: 73400320, Swap: 0, Limit: 524288000}},
+		{statsCgroupV2, 83886080, 0, domain.MemoryStats{RSS: 62914560, Cache: 31457280}},
+		{statsPercpu, 40000000, 40, domain.MemoryStats{RSS: 40000000, Cache: 10000000, Limit: 100000000}},
+		{statsOneShot, 0, 0, domain.MemoryStats{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.fixture, func(t *testing.T) {
+			used, usage, details := getMemoryStats(readStats(t, test.fixture))
+
+			if used != test.used {
+				t.Errorf("used = %d, want %d", used, test.used)
+			}
+			if round(usage) != test.usage {
+				t.Errorf("usage = %v, want %v", usage, test.usage)
+			}
+			if details != test.details {
+				t.Errorf("details = %+v, want %+v", details, test.details)
+			}
+		})
+	}
+}
+
+func TestGetNetworkStats(t *testing.T) {
+	tests := []struct {
+		fixture  string
+		networks []domain.NetworkStats
+	}{
+		{statsCgroupV1, []domain.NetworkStats{
+			{Interface: "eth0", RxBytes: 1200, RxPackets: 12, RxErrors: 1, RxDropped: 2, TxBytes: 2400, TxPackets: 24},
+			{Interface: "eth1", RxBytes: 300, RxPackets: 3, TxBytes: 400, TxPackets: 4, TxDropped: 1},
+		}},
+		{statsCgroupV2, []domain.NetworkStats{
+			{Interface: "eth0", RxBytes: 5000, RxPackets: 50, TxBytes: 7000, TxPackets: 70},
+		}},
+		{statsPercpu, []domain.NetworkStats{
+			{Interface: "eth0", RxBytes: 10, RxPackets: 1, TxBytes: 20, TxPackets: 2},
+		}},
+		{statsOneShot, []domain.NetworkStats{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.fixture, func(t *testing.T) {
+			networks := getNetworkStats(readStats(t, test.fixture))
+
+			if !reflect.DeepEqual(networks, test.networks) {
+				t.Errorf("networks = %+v, want %+v", networks, test.networks)
+			}
+		})
+	}
+}
+
+func TestGetBlockIOStats(t *testing.T) {
+	tests := []struct {
+		fixture string
+		blockIO domain.BlockIOStats
+	}{
+		{statsCgroupV1, domain.BlockIOStats{ReadBytes: 5120, WriteBytes: 8192, ReadOps: 4, WriteOps: 5}},
+		{statsCgroupV2, domain.BlockIOStats{ReadBytes: 1052672, WriteBytes: 2097152}},
+		{statsPercpu, domain.BlockIOStats{}},
+		{statsOneShot, domain.BlockIOStats{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.fixture, func(t *testing.T) {
+			blockIO := getBlockIOStats(readStats(t, test.fixture))
+
+			if blockIO != test.blockIO {
+				t.Errorf("block IO = %+v, want %+v", blockIO, test.blockIO)
+			}
+		})
+	}
+}
+
+func TestGetPidsStats(t *testing.T) {
+	tests := []struct {
+		fixture string
+		pids    domain.PidsStats
+	}{
+		{statsCgroupV1, domain.PidsStats{Current: 12}},
+		{statsCgroupV2, domain.PidsStats{Current: 5, Limit: 4915}},
+		{statsPercpu, domain.PidsStats{}},
+		{statsOneShot, domain.PidsStats{Current: 5, Limit: 4915}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.fixture, func(t *testing.T) {
+			pids := getPidsStats(readStats(t, test.fixture))
+
+			if pids != test.pids {
+				t.Errorf("pids = %+v, want %+v", pids, test.pids)
+			}
+		})
+	}
+}
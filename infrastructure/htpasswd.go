@@ -0,0 +1,152 @@
+package infrastructure
+
+import (
+	"bufio"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	apr1Prefix = "$apr1$"
+	sha1Prefix = "{SHA}"
+	apr1Itoa64 = "./0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+)
+
+//htpasswd verifies passwords hashed with bcrypt, apr1 MD5 or SHA1 like htpasswd does
+type htpasswd struct {
+	hashes map[string]string
+}
+
+//LoadHtpasswd reads user:hash lines of an htpasswd file
+func LoadHtpasswd(path string) (*htpasswd, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	result := htpasswd{hashes: make(map[string]string)}
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		parts := strings.SplitN(text, ":", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("%s:%d: expected user:hash", path, line)
+		}
+		if !isSupportedHash(parts[1]) {
+			return nil, fmt.Errorf("%s:%d: unsupported hash of user %s, use bcrypt, apr1 or SHA1", path, line, parts[0])
+		}
+		result.hashes[parts[0]] = parts[1]
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (h htpasswd) Verify(user string, password string) bool {
+	hash, ok := h.hashes[user]
+	if !ok {
+		return false
+	}
+
+	switch {
+	case strings.HasPrefix(hash, "$2"):
+		return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+	case strings.HasPrefix(hash, apr1Prefix):
+		salt := strings.SplitN(strings.TrimPrefix(hash, apr1Prefix), "$", 2)[0]
+		return constantTimeEqual(apr1(password, salt), hash)
+	case strings.HasPrefix(hash, sha1Prefix):
+		sum := sha1.Sum([]byte(password))
+		return constantTimeEqual(sha1Prefix+base64.StdEncoding.EncodeToString(sum[:]), hash)
+	default:
+		return false
+	}
+}
+
+func isSupportedHash(hash string) bool {
+	return strings.HasPrefix(hash, "$2") || strings.HasPrefix(hash, apr1Prefix) || strings.HasPrefix(hash, sha1Prefix)
+}
+
+func constantTimeEqual(a string, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
+//apr1 returns Apache variant of MD5-crypt of the password
+func apr1(password string, salt string) string {
+	if len(salt) > 8 {
+		salt = salt[:8]
+	}
+
+	alternate := md5.Sum([]byte(password + salt + password))
+
+	digest := md5.New()
+	digest.Write([]byte(password + apr1Prefix + salt))
+	for i := len(password); i > 0; i -= 16 {
+		if i > 16 {
+			digest.Write(alternate[:])
+		} else {
+			digest.Write(alternate[:i])
+		}
+	}
+	for i := len(password); i > 0; i >>= 1 {
+		if i&1 == 1 {
+			digest.Write([]byte{0})
+		} else {
+			digest.Write([]byte{password[0]})
+		}
+	}
+	final := digest.Sum(nil)
+
+	for i := 0; i < 1000; i++ {
+		round := md5.New()
+		if i&1 == 1 {
+			round.Write([]byte(password))
+		} else {
+			round.Write(final)
+		}
+		if i%3 != 0 {
+			round.Write([]byte(salt))
+		}
+		if i%7 != 0 {
+			round.Write([]byte(password))
+		}
+		if i&1 == 1 {
+			round.Write(final)
+		} else {
+			round.Write([]byte(password))
+		}
+		final = round.Sum(nil)
+	}
+
+	var result strings.Builder
+	result.WriteString(apr1Prefix + salt + "$")
+	encode := func(a, b, c byte, n int) {
+		value := uint(a)<<16 | uint(b)<<8 | uint(c)
+		for ; n > 0; n-- {
+			result.WriteByte(apr1Itoa64[value&0x3f])
+			value >>= 6
+		}
+	}
+	encode(final[0], final[6], final[12], 4)
+	encode(final[1], final[7], final[13], 4)
+	encode(final[2], final[8], final[14], 4)
+	encode(final[3], final[9], final[15], 4)
+	encode(final[4], final[10], final[5], 4)
+	encode(0, 0, final[11], 2)
+
+	return result.String()
+}
@@ -0,0 +1,152 @@
+package infrastructure
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"godtop/domain"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	stdinStream  = "stdin"
+	stdoutStream = "stdout"
+	stderrStream = "stderr"
+
+	logHeaderSize = 8
+	//maxLineSize bounds buffered log lines, longer lines are split into several entries
+	maxLineSize = 1024 * 1024
+)
+
+//logReader splits docker logs into lines,
+//the stream is multiplexed into frames with an 8 bytes header unless the container has a TTY
+type logReader struct {
+	tty        bool
+	timestamps bool
+}
+
+//read emits every line of the logs until the end of logs or emit returns false
+func (r logReader) read(logs io.Reader, emit func(domain.LogEntry) bool) {
+	if r.tty {
+		r.readRaw(logs, emit)
+		return
+	}
+
+	var header [logHeaderSize]byte
+	pending := map[string]*bytes.Buffer{}
+	defer func() {
+		for stream, buffer := range pending {
+			if buffer.Len() > 0 {
+				emit(r.entry(stream, buffer.String()))
+			}
+		}
+	}()
+
+	for {
+		if _, err := io.ReadFull(logs, header[:]); err != nil {
+			return
+		}
+
+		stream := frameStream(header[0])
+		size := binary.BigEndian.Uint32(header[4:])
+
+		buffer, ok := pending[stream]
+		if !ok {
+			buffer = &bytes.Buffer{}
+			pending[stream] = buffer
+		}
+
+		//a frame may hold several lines or only a part of a line, it is buffered in parts to bound the buffer
+		for remaining := int64(size); remaining > 0; {
+			part := remaining
+			if room := int64(maxLineSize - buffer.Len()); part > room {
+				part = room
+			}
+			if _, err := io.CopyN(buffer, logs, part); err != nil {
+				return
+			}
+			remaining -= part
+
+			if !r.emitLines(stream, buffer, emit) {
+				return
+			}
+		}
+	}
+}
+
+//emitLines emits complete lines of the buffer, a part of a line which fills the buffer is emitted as a line
+func (r logReader) emitLines(stream string, buffer *bytes.Buffer, emit func(domain.LogEntry) bool) bool {
+	for {
+		index := bytes.IndexByte(buffer.Bytes(), '\n')
+		if index < 0 {
+			break
+		}
+
+		line := string(buffer.Next(index + 1))
+		if !emit(r.entry(stream, strings.TrimSuffix(line, "\n"))) {
+			return false
+		}
+	}
+
+	if buffer.Len() >= maxLineSize {
+		line := buffer.String()
+		buffer.Reset()
+		return emit(r.entry(stream, line))
+	}
+
+	return true
+}
+
+//readRaw emits lines of TTY logs, a part of a line which fills the buffer is emitted as a line
+func (r logReader) readRaw(logs io.Reader, emit func(domain.LogEntry) bool) {
+	reader := bufio.NewReaderSize(logs, maxLineSize)
+	for {
+		line, err := reader.ReadSlice('\n')
+		if len(line) > 0 {
+			text := strings.TrimSuffix(strings.TrimSuffix(string(line), "\n"), "\r")
+			if !emit(r.entry(stdoutStream, text)) {
+				return
+			}
+		}
+		if err != nil && err != bufio.ErrBufferFull {
+			return
+		}
+	}
+}
+
+//entry splits the timestamp prefix off the line when timestamps are requested
+func (r logReader) entry(stream string, line string) domain.LogEntry {
+	entry := domain.LogEntry{
+		Stream: stream,
+		Line:   line,
+	}
+
+	if !r.timestamps {
+		return entry
+	}
+
+	index := strings.IndexByte(line, ' ')
+	if index < 0 {
+		index = len(line)
+	}
+
+	if timestamp, err := time.Parse(time.RFC3339Nano, line[:index]); err == nil {
+		entry.Timestamp = &timestamp
+		entry.Line = strings.TrimPrefix(line[index:], " ")
+	}
+
+	return entry
+}
+
+func frameStream(kind byte) string {
+	switch kind {
+	case 0:
+		return stdinStream
+	case 2:
+		return stderrStream
+	default:
+		return stdoutStream
+	}
+}
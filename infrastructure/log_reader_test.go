@@ -0,0 +1,70 @@
+package infrastructure
+
+import (
+	"bytes"
+	"encoding/binary"
+	"godtop/domain"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+//multiplex frames the logs as the daemon does for containers without a TTY, a frame holds at most frameSize bytes
+func multiplex(stream byte, logs string, frameSize int) []byte {
+	var result bytes.Buffer
+	for len(logs) > 0 {
+		size := frameSize
+		if size > len(logs) {
+			size = len(logs)
+		}
+
+		header := [logHeaderSize]byte{stream}
+		binary.BigEndian.PutUint32(header[4:], uint32(size))
+		result.Write(header[:])
+		result.WriteString(logs[:size])
+		logs = logs[size:]
+	}
+
+	return result.Bytes()
+}
+
+func TestLogReaderSplitsLongLines(t *testing.T) {
+	long := strings.Repeat("a", maxLineSize) + strings.Repeat("b", 10)
+	logs := "first\n" + long + "\nlast"
+	want := []domain.LogEntry{
+		{Stream: stdoutStream, Line: "first"},
+		{Stream: stdoutStream, Line: strings.Repeat("a", maxLineSize)},
+		{Stream: stdoutStream, Line: strings.Repeat("b", 10)},
+		{Stream: stdoutStream, Line: "last"},
+	}
+
+	tests := []struct {
+		name string
+		tty  bool
+		logs []byte
+	}{
+		{"tty", true, []byte(logs)},
+		{"tty with carriage returns", true, []byte(strings.Replace(logs, "\n", "\r\n", -1))},
+		{"multiplexed in one frame", false, multiplex(1, logs, len(logs))},
+		{"multiplexed in small frames", false, multiplex(1, logs, 4096)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var entries []domain.LogEntry
+			logReader{tty: test.tty}.read(bytes.NewReader(test.logs), func(entry domain.LogEntry) bool {
+				entries = append(entries, entry)
+				return true
+			})
+
+			if !reflect.DeepEqual(entries, want) {
+				t.Errorf("got %d entries, want %d", len(entries), len(want))
+				for i := 0; i < len(entries) && i < len(want); i++ {
+					if !reflect.DeepEqual(entries[i], want[i]) {
+						t.Errorf("entry %d has %d bytes, want %d", i, len(entries[i].Line), len(want[i].Line))
+					}
+				}
+			}
+		})
+	}
+}
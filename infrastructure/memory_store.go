@@ -0,0 +1,122 @@
+package infrastructure
+
+import (
+	"context"
+	"godtop/domain"
+	"sync"
+	"time"
+)
+
+type memoryStore struct {
+	capacity  int
+	retention time.Duration
+
+	mu        sync.RWMutex
+	series    map[string]*ringBuffer
+	lastSweep time.Time
+}
+
+//CreateMemoryStore returns a store keeping up to capacity buckets per series,
+//series without buckets newer than retention are dropped
+func CreateMemoryStore(capacity int, retention time.Duration) *memoryStore {
+	return &memoryStore{
+		capacity:  capacity,
+		retention: retention,
+		series:    make(map[string]*ringBuffer),
+	}
+}
+
+func (s *memoryStore) Append(ctx context.Context, series string, bucket domain.MetricsBucket) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	buffer, ok := s.series[series]
+	if !ok {
+		buffer = newRingBuffer(s.capacity)
+		s.series[series] = buffer
+	}
+	buffer.push(bucket)
+
+	if time.Since(s.lastSweep) > s.retention {
+		s.sweep()
+	}
+
+	return nil
+}
+
+func (s *memoryStore) Range(ctx context.Context, series string, from time.Time, to time.Time) ([]domain.MetricsBucket, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	buffer, ok := s.series[series]
+	if !ok {
+		return nil, nil
+	}
+
+	return buffer.between(from, to), nil
+}
+
+//sweep drops series which were not updated during retention
+func (s *memoryStore) sweep() {
+	deadline := time.Now().Add(-s.retention)
+	for name, buffer := range s.series {
+		if last, ok := buffer.last(); !ok || last.Time.Before(deadline) {
+			delete(s.series, name)
+		}
+	}
+	s.lastSweep = time.Now()
+}
+
+//region Ring Buffer
+
+type ringBuffer struct {
+	items []domain.MetricsBucket
+	head  int
+	size  int
+}
+
+func newRingBuffer(capacity int) *ringBuffer {
+	return &ringBuffer{
+		items: make([]domain.MetricsBucket, capacity),
+	}
+}
+
+func (b *ringBuffer) push(bucket domain.MetricsBucket) {
+	if len(b.items) == 0 {
+		return
+	}
+
+	b.items[(b.head+b.size)%len(b.items)] = bucket
+	if b.size < len(b.items) {
+		b.size++
+	} else {
+		b.head = (b.head + 1) % len(b.items)
+	}
+}
+
+func (b *ringBuffer) at(i int) domain.MetricsBucket {
+	return b.items[(b.head+i)%len(b.items)]
+}
+
+func (b *ringBuffer) last() (domain.MetricsBucket, bool) {
+	if b.size == 0 {
+		return domain.MetricsBucket{}, false
+	}
+	return b.at(b.size - 1), true
+}
+
+//between returns buckets within [from, to] in time order
+func (b *ringBuffer) between(from time.Time, to time.Time) []domain.MetricsBucket {
+	var result []domain.MetricsBucket
+	for i := 0; i < b.size; i++ {
+		bucket := b.at(i)
+		if bucket.Time.Before(from) || bucket.Time.After(to) {
+			continue
+		}
+		result = append(result, bucket)
+	}
+
+	return result
+}
+
+//endregion
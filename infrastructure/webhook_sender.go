@@ -0,0 +1,82 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+type webhookSender struct {
+	client *http.Client
+}
+
+func CreateWebhookSender(timeout time.Duration) *webhookSender {
+	return &webhookSender{
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+//CreateTLSWebhookSender trusts servers signed by the CA bundle besides the system roots
+//and presents the client certificate when one is given
+func CreateTLSWebhookSender(timeout time.Duration, caFile string, certFile string, keyFile string) (*webhookSender, error) {
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if caFile != "" {
+		bundle, err := ioutil.ReadFile(caFile)
+		if err != nil {
+			return nil, err
+		}
+
+		roots, err := x509.SystemCertPool()
+		if err != nil {
+			roots = x509.NewCertPool()
+		}
+		if !roots.AppendCertsFromPEM(bundle) {
+			return nil, fmt.Errorf("no certificates found in %s", caFile)
+		}
+		config.RootCAs = roots
+	}
+
+	if certFile != "" || keyFile != "" {
+		certificate, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, err
+		}
+		config.Certificates = []tls.Certificate{certificate}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = config
+
+	return &webhookSender{
+		client: &http.Client{Timeout: timeout, Transport: transport},
+	}, nil
+}
+
+//Post sends a JSON body and returns the response status code
+func (s webhookSender) Post(ctx context.Context, url string, body []byte, headers map[string]string) (int, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	for name, value := range headers {
+		request.Header.Set(name, value)
+	}
+
+	response, err := s.client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+	io.Copy(ioutil.Discard, response.Body)
+
+	return response.StatusCode, nil
+}
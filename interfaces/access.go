@@ -0,0 +1,69 @@
+package interfaces
+
+import (
+	"godtop/application"
+	"godtop/domain"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+//authorize evaluates the access policy for the route,
+//routes of a single container are checked against container scopes of the role bindings,
+//other routes, listing containers or events among others, require a binding without a scope
+func (h Handler) authorize(ctx *gin.Context) {
+	if h.AccessPolicy == nil {
+		ctx.Next()
+		return
+	}
+
+	principal := principalOf(ctx)
+	required := requiredRole(ctx.Request.Method, apiRoute(ctx.FullPath()))
+
+	allowed := false
+	if nameOrId := ctx.Param("nameOrId"); nameOrId != "" {
+		interactor := application.ContainerInteractor{
+			Service: h.dockerService(ctx),
+		}
+
+		container, err := interactor.Inspect(ctx, nameOrId)
+		if err != nil {
+			container = nil
+		}
+		allowed = h.AccessPolicy.AllowsContainer(principal, required, container)
+	} else {
+		allowed = h.AccessPolicy.Allows(principal, required)
+	}
+
+	if !allowed {
+		Error(ctx, http.StatusForbidden, nil, principal.Name+" requires "+required+" role")
+		return
+	}
+
+	ctx.Next()
+}
+
+//routeRoles are routes requiring a role regardless of the method,
+//fleet agents push reports with the agent role so they need no role to run actions on containers
+var routeRoles = map[string]string{
+	"/api/config":       domain.AdminRole,
+	"/api/fleet/ingest": domain.AgentRole,
+}
+
+//requiredRole returns the role of the route when it has one,
+//otherwise viewer for reading, operator for POST actions and admin for other mutations
+func requiredRole(method string, route string) string {
+	if role, ok := routeRoles[route]; ok {
+		return role
+	}
+
+	switch strings.ToUpper(method) {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return domain.ViewerRole
+	case http.MethodPost:
+		return domain.OperatorRole
+	default:
+		return domain.AdminRole
+	}
+}
@@ -0,0 +1,105 @@
+package interfaces
+
+import (
+	"context"
+	"errors"
+	"godtop/application"
+	"godtop/domain"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+//inspectOnlyService answers inspection of its containers, other methods of the service are not used by the tests
+type inspectOnlyService struct {
+	domain.DockerService
+	containers map[string]*domain.ContainerDetails
+}
+
+func (s inspectOnlyService) InspectContainer(ctx context.Context, idOrName string) (*domain.ContainerDetails, error) {
+	if container, ok := s.containers[idOrName]; ok {
+		details := *container
+		return &details, nil
+	}
+
+	return nil, domain.NewError(domain.ErrNotFound, errors.New("no such container: "+idOrName))
+}
+
+func TestRequiredRole(t *testing.T) {
+	tests := []struct {
+		method string
+		route  string
+		want   string
+	}{
+		{http.MethodGet, "/api/containers", domain.ViewerRole},
+		{http.MethodHead, "/api/host", domain.ViewerRole},
+		{http.MethodPost, "/api/container/:nameOrId/start", domain.OperatorRole},
+		{http.MethodDelete, "/api/container/:nameOrId", domain.AdminRole},
+		{http.MethodGet, "/api/config", domain.AdminRole},
+		{http.MethodPost, "/api/fleet/ingest", domain.AgentRole},
+	}
+
+	for _, test := range tests {
+		if got := requiredRole(test.method, test.route); got != test.want {
+			t.Errorf("%s %s requires %s, want %s", test.method, test.route, got, test.want)
+		}
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	engines := application.NewEngines()
+	engines.Add("local", inspectOnlyService{containers: map[string]*domain.ContainerDetails{
+		"web": {Container: domain.Container{ID: "web-id", Names: []string{"web"}}, Labels: map[string]string{"team": "shop"}},
+		"db":  {Container: domain.Container{ID: "db-id", Names: []string{"db"}}},
+	}})
+
+	policy, err := application.NewAccessPolicy([]domain.RoleBinding{
+		{Principals: []string{"admin"}, Role: domain.AdminRole},
+		{Principals: []string{"shop"}, Role: domain.ViewerRole, Containers: domain.ContainerScope{Labels: map[string]string{"team": "shop"}}},
+		{Principals: []string{"agent"}, Role: domain.ViewerRole},
+		{Principals: []string{"agent"}, Role: domain.AgentRole},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h := Handler{
+		Engines:        engines,
+		Fleet:          application.NewFleet("server", time.Minute),
+		AccessPolicy:   policy,
+		Authenticators: []Authenticator{TokenAuthenticator{"admin-token": "admin", "shop-token": "shop", "agent-token": "agent"}},
+	}
+	router := h.routes()
+
+	tests := []struct {
+		token  string
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{"shop-token", http.MethodGet, "/api/v2/container/web", "", http.StatusOK},
+		{"shop-token", http.MethodGet, "/api/v2/container/db", "", http.StatusForbidden},
+		{"shop-token", http.MethodGet, "/api/v2/container/missing", "", http.StatusForbidden},
+		{"shop-token", http.MethodGet, "/api/v2/events", "", http.StatusForbidden},
+		{"admin-token", http.MethodGet, "/api/v2/container/missing", "", http.StatusNotFound},
+		{"agent-token", http.MethodPost, "/api/v2/fleet/ingest", `{"node":"agent-1","snapshot":{}}`, http.StatusOK},
+		{"agent-token", http.MethodPost, "/api/v2/container/web/start", "", http.StatusForbidden},
+		{"agent-token", http.MethodGet, "/api/v2/config", "", http.StatusForbidden},
+		{"shop-token", http.MethodPost, "/api/v2/fleet/ingest", `{"node":"shop-1","snapshot":{}}`, http.StatusForbidden},
+	}
+
+	for _, test := range tests {
+		request := httptest.NewRequest(test.method, test.path, strings.NewReader(test.body))
+		request.Header.Set("Authorization", "Bearer "+test.token)
+		recorder := httptest.NewRecorder()
+
+		router.ServeHTTP(recorder, request)
+
+		if recorder.Code != test.status {
+			t.Errorf("%s %s with %s: status = %d, want %d: %s", test.method, test.path, test.token, recorder.Code, test.status, recorder.Body)
+		}
+	}
+}
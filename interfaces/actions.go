@@ -0,0 +1,203 @@
+package interfaces
+
+import (
+	"fmt"
+	"godtop/application"
+	"godtop/domain"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+//region Action Handlers
+
+// startContainer godoc
+// @Summary Starts a container
+// @Description /api/v2 responds in the envelope, data holds domain.Container
+// @Produce json
+// @Param nameOrId path string true "container Name or Id"
+// @Success 200 {object} domain.Container
+// @Router /container/{nameOrId}/start [post]
+func (h Handler) startContainer(ctx *gin.Context) {
+	h.containerAction(ctx, func(interactor *application.ContainerInteractor, nameOrId string) (*domain.Container, error) {
+		return interactor.Start(ctx, nameOrId)
+	})
+}
+
+// stopContainer godoc
+// @Summary Stops a container, killing it after the timeout
+// @Description /api/v2 responds in the envelope, data holds domain.Container
+// @Produce json
+// @Param nameOrId path string true "container Name or Id"
+// @Param timeout query string false "seconds or duration to wait before killing the container"
+// @Success 200 {object} domain.Container
+// @Router /container/{nameOrId}/stop [post]
+func (h Handler) stopContainer(ctx *gin.Context) {
+	timeout, err := parseTimeout(ctx)
+	if err != nil {
+		Error(ctx, http.StatusBadRequest, err, err.Error())
+		return
+	}
+
+	h.containerAction(ctx, func(interactor *application.ContainerInteractor, nameOrId string) (*domain.Container, error) {
+		return interactor.Stop(ctx, nameOrId, timeout)
+	})
+}
+
+// restartContainer godoc
+// @Summary Restarts a container, killing it after the timeout
+// @Description /api/v2 responds in the envelope, data holds domain.Container
+// @Produce json
+// @Param nameOrId path string true "container Name or Id"
+// @Param timeout query string false "seconds or duration to wait before killing the container"
+// @Success 200 {object} domain.Container
+// @Router /container/{nameOrId}/restart [post]
+func (h Handler) restartContainer(ctx *gin.Context) {
+	timeout, err := parseTimeout(ctx)
+	if err != nil {
+		Error(ctx, http.StatusBadRequest, err, err.Error())
+		return
+	}
+
+	h.containerAction(ctx, func(interactor *application.ContainerInteractor, nameOrId string) (*domain.Container, error) {
+		return interactor.Restart(ctx, nameOrId, timeout)
+	})
+}
+
+// pauseContainer godoc
+// @Summary Pauses all processes of a container
+// @Description /api/v2 responds in the envelope, data holds domain.Container
+// @Produce json
+// @Param nameOrId path string true "container Name or Id"
+// @Success 200 {object} domain.Container
+// @Router /container/{nameOrId}/pause [post]
+func (h Handler) pauseContainer(ctx *gin.Context) {
+	h.containerAction(ctx, func(interactor *application.ContainerInteractor, nameOrId string) (*domain.Container, error) {
+		return interactor.Pause(ctx, nameOrId)
+	})
+}
+
+// unpauseContainer godoc
+// @Summary Resumes all processes of a container
+// @Description /api/v2 responds in the envelope, data holds domain.Container
+// @Produce json
+// @Param nameOrId path string true "container Name or Id"
+// @Success 200 {object} domain.Container
+// @Router /container/{nameOrId}/unpause [post]
+func (h Handler) unpauseContainer(ctx *gin.Context) {
+	h.containerAction(ctx, func(interactor *application.ContainerInteractor, nameOrId string) (*domain.Container, error) {
+		return interactor.Unpause(ctx, nameOrId)
+	})
+}
+
+// killContainer godoc
+// @Summary Sends a signal to a container
+// @Description /api/v2 responds in the envelope, data holds domain.Container
+// @Produce json
+// @Param nameOrId path string true "container Name or Id"
+// @Param signal query string false "signal to send, SIGKILL by default"
+// @Success 200 {object} domain.Container
+// @Router /container/{nameOrId}/kill [post]
+func (h Handler) killContainer(ctx *gin.Context) {
+	signal := ctx.Query("signal")
+
+	h.containerAction(ctx, func(interactor *application.ContainerInteractor, nameOrId string) (*domain.Container, error) {
+		return interactor.Kill(ctx, nameOrId, signal)
+	})
+}
+
+// removeContainer godoc
+// @Summary Removes a container
+// @Description /api/v2 responds in the envelope, data holds domain.Container
+// @Produce json
+// @Param nameOrId path string true "container Name or Id"
+// @Param force query bool false "kill the container if it is running"
+// @Param volumes query bool false "remove anonymous volumes of the container"
+// @Success 200 {object} domain.Container
+// @Router /container/{nameOrId} [delete]
+func (h Handler) removeContainer(ctx *gin.Context) {
+	force, err := parseBoolQuery(ctx, "force")
+	if err != nil {
+		Error(ctx, http.StatusBadRequest, err, err.Error())
+		return
+	}
+
+	volumes, err := parseBoolQuery(ctx, "volumes")
+	if err != nil {
+		Error(ctx, http.StatusBadRequest, err, err.Error())
+		return
+	}
+
+	h.containerAction(ctx, func(interactor *application.ContainerInteractor, nameOrId string) (*domain.Container, error) {
+		return interactor.Remove(ctx, nameOrId, force, volumes)
+	})
+}
+
+//endregion
+
+func (h Handler) containerAction(ctx *gin.Context, action func(*application.ContainerInteractor, string) (*domain.Container, error)) {
+	nameOrId := ctx.Param("nameOrId")
+
+	interactor := application.ContainerInteractor{
+		Service: h.dockerService(ctx),
+	}
+
+	container, err := action(&interactor, nameOrId)
+	if err != nil {
+		Fail(ctx, err)
+		return
+	}
+
+	Ok(ctx, container)
+}
+
+//parseTimeout accepts whole non-negative seconds or a duration, nil means the engine default,
+//the engine stops containers with a timeout in seconds
+func parseTimeout(ctx *gin.Context) (*time.Duration, error) {
+	timeout, err := parseDurationQuery(ctx, "timeout")
+	if err != nil || timeout == nil {
+		return nil, err
+	}
+
+	if *timeout < 0 || *timeout%time.Second != 0 {
+		return nil, fmt.Errorf("invalid timeout: %s is not a non-negative whole number of seconds", timeout)
+	}
+
+	return timeout, nil
+}
+
+//parseDurationQuery accepts seconds or a duration, nil means the parameter is not set
+func parseDurationQuery(ctx *gin.Context, name string) (*time.Duration, error) {
+	value := ctx.Query(name)
+	if value == "" {
+		return nil, nil
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		result := time.Duration(seconds) * time.Second
+		return &result, nil
+	}
+
+	result, err := time.ParseDuration(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", name, err)
+	}
+
+	return &result, nil
+}
+
+func parseBoolQuery(ctx *gin.Context, name string) (bool, error) {
+	value := ctx.Query(name)
+	if value == "" {
+		return false, nil
+	}
+
+	result, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s: %w", name, err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,40 @@
+package interfaces
+
+import (
+	"godtop/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+//region Alert Handlers
+
+// getAlerts godoc
+// @Summary Retrieves pending, firing and recently resolved alerts
+// @Description /api/v2 responds in the envelope, data.alerts holds domain.Alert list
+// @Produce json
+// @Param state query string false "pending, firing or resolved"
+// @Success 200 {array} domain.Alert
+// @Router /alerts [get]
+func (h Handler) getAlerts(ctx *gin.Context) {
+	type payload struct {
+		Alerts []domain.Alert `json:"alerts"`
+	}
+
+	Ok(ctx, payload{Alerts: h.AlertEngine.Alerts(ctx.Query("state"))})
+}
+
+// getAlertRules godoc
+// @Summary Retrieves configured alert rules
+// @Description /api/v2 responds in the envelope, data.rules holds domain.AlertRule list
+// @Produce json
+// @Success 200 {array} domain.AlertRule
+// @Router /alerts/rules [get]
+func (h Handler) getAlertRules(ctx *gin.Context) {
+	type payload struct {
+		Rules []domain.AlertRule `json:"rules"`
+	}
+
+	Ok(ctx, payload{Rules: h.AlertEngine.Rules()})
+}
+
+//endregion
@@ -0,0 +1,198 @@
+package interfaces
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"godtop/domain"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	principalKey     = "principal"
+	authRealm        = "godtop"
+	accessTokenQuery = "access_token"
+)
+
+var (
+	anonymous = domain.Principal{Name: "anonymous", Method: "none"}
+
+	errInvalidCredentials = errors.New("invalid credentials")
+)
+
+//Authenticator identifies the caller of a request
+type Authenticator interface {
+	//Authenticate returns nil when the request has no credentials of this kind
+	//and an error when the credentials are invalid
+	Authenticate(r *http.Request) (*domain.Principal, error)
+	//Challenge returns the WWW-Authenticate value asking for credentials of this kind
+	Challenge() string
+}
+
+//TokenAuthenticator accepts static bearer tokens mapped to principal names,
+//the access_token query parameter is accepted for EventSource and WebSocket clients which cannot set headers
+type TokenAuthenticator map[string]string
+
+func (a TokenAuthenticator) Authenticate(r *http.Request) (*domain.Principal, error) {
+	var presented []byte
+	if header := r.Header.Get("Authorization"); strings.HasPrefix(header, "Bearer ") {
+		presented = []byte(strings.TrimSpace(strings.TrimPrefix(header, "Bearer ")))
+	} else if token := r.URL.Query().Get(accessTokenQuery); token != "" {
+		presented = []byte(token)
+	} else {
+		return nil, nil
+	}
+
+	for token, name := range a {
+		if subtle.ConstantTimeCompare(presented, []byte(token)) == 1 {
+			return &domain.Principal{Name: name, Method: "token"}, nil
+		}
+	}
+
+	return nil, errInvalidCredentials
+}
+
+func (a TokenAuthenticator) Challenge() string {
+	return fmt.Sprintf("Bearer realm=%q", authRealm)
+}
+
+//BasicAuthenticator accepts HTTP basic credentials checked by the verifier
+type BasicAuthenticator struct {
+	Verifier domain.PasswordVerifier
+}
+
+func (a BasicAuthenticator) Authenticate(r *http.Request) (*domain.Principal, error) {
+	user, password, ok := r.BasicAuth()
+	if !ok {
+		return nil, nil
+	}
+
+	if !a.Verifier.Verify(user, password) {
+		return nil, errInvalidCredentials
+	}
+
+	return &domain.Principal{Name: user, Method: "basic"}, nil
+}
+
+func (a BasicAuthenticator) Challenge() string {
+	return fmt.Sprintf("Basic realm=%q", authRealm)
+}
+
+//CertAuthenticator accepts verified TLS client certificates, the principal is the subject common name
+type CertAuthenticator struct{}
+
+func (a CertAuthenticator) Authenticate(r *http.Request) (*domain.Principal, error) {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 {
+		return nil, nil
+	}
+
+	subject := r.TLS.VerifiedChains[0][0].Subject
+	name := subject.CommonName
+	if name == "" {
+		name = subject.String()
+	}
+
+	return &domain.Principal{Name: name, Method: "cert"}, nil
+}
+
+//Challenge is empty since client certificates are requested during the TLS handshake
+func (a CertAuthenticator) Challenge() string {
+	return ""
+}
+
+//authenticate attributes every request to a principal,
+//without authenticators every request is attributed to the anonymous principal,
+//requests without a verified client certificate are rejected when a client CA is configured
+func (h Handler) authenticate(ctx *gin.Context) {
+	if h.TLS != nil && h.TLS.ClientCAFile != "" && (ctx.Request.TLS == nil || len(ctx.Request.TLS.VerifiedChains) == 0) {
+		Error(ctx, http.StatusUnauthorized, nil, "client certificate required")
+		return
+	}
+
+	if len(h.Authenticators) == 0 {
+		ctx.Set(principalKey, anonymous)
+		ctx.Next()
+		return
+	}
+
+	var challenges []string
+	for _, authenticator := range h.Authenticators {
+		principal, err := authenticator.Authenticate(ctx.Request)
+		if err != nil {
+			Error(ctx, http.StatusUnauthorized, err, err.Error())
+			return
+		}
+		if principal != nil {
+			ctx.Set(principalKey, *principal)
+			ctx.Next()
+			return
+		}
+		if challenge := authenticator.Challenge(); challenge != "" {
+			challenges = append(challenges, challenge)
+		}
+	}
+
+	for _, challenge := range challenges {
+		ctx.Writer.Header().Add("WWW-Authenticate", challenge)
+	}
+	Error(ctx, http.StatusUnauthorized, nil, "authentication required")
+}
+
+//principalOf returns the principal of an authenticated request
+func principalOf(ctx *gin.Context) domain.Principal {
+	if principal, ok := ctx.Get(principalKey); ok {
+		return principal.(domain.Principal)
+	}
+
+	return anonymous
+}
+
+//logFormatter is the gin default log line with the principal of the request and the access token redacted
+func logFormatter(params gin.LogFormatterParams) string {
+	principal := "-"
+	if value, ok := params.Keys[principalKey]; ok {
+		principal = value.(domain.Principal).Name
+	}
+
+	return fmt.Sprintf("[GIN] %v | %3d | %13v | %15s | %-10s | %-7s %#v\n%s",
+		params.TimeStamp.Format("2006/01/02 - 15:04:05"),
+		params.StatusCode,
+		params.Latency.Round(time.Microsecond),
+		params.ClientIP,
+		principal,
+		params.Method,
+		loggedPath(params.Path),
+		params.ErrorMessage,
+	)
+}
+
+//loggedPath returns the path with values of the access token query parameter redacted,
+//parameters are split by semicolons too since older clients and servers accept them as separators
+func loggedPath(path string) string {
+	index := strings.IndexByte(path, '?')
+	if index < 0 {
+		return path
+	}
+
+	var result strings.Builder
+	result.WriteString(path[:index+1])
+	for query := path[index+1:]; query != ""; {
+		param, separator := query, ""
+		if end := strings.IndexAny(query, "&;"); end >= 0 {
+			param, separator = query[:end], query[end:end+1]
+		}
+		query = query[len(param)+len(separator):]
+
+		if name, err := url.QueryUnescape(strings.SplitN(param, "=", 2)[0]); err != nil || name == accessTokenQuery {
+			param = accessTokenQuery + "=redacted"
+		}
+		result.WriteString(param + separator)
+	}
+
+	return result.String()
+}
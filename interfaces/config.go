@@ -0,0 +1,29 @@
+package interfaces
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+//region Config Handlers
+
+// getConfig godoc
+// @Summary Retrieves the effective configuration with secrets redacted, durations are written like in configuration files
+// @Description /api/v2 responds in the envelope, data.config holds config.Config
+// @Produce json
+// @Success 200 {object} config.Config
+// @Router /config [get]
+func (h Handler) getConfig(ctx *gin.Context) {
+	document, err := h.Config.Redacted().Document()
+	if err != nil {
+		Fail(ctx, err)
+		return
+	}
+
+	type payload struct {
+		Config map[string]interface{} `json:"config"`
+	}
+
+	Ok(ctx, payload{Config: document})
+}
+
+//endregion
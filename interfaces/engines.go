@@ -0,0 +1,82 @@
+package interfaces
+
+import (
+	"godtop/application"
+	"godtop/domain"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	engineKey        = "engine"
+	dockerServiceKey = "dockerService"
+	engineRoutePath  = "/api/engines/:engine"
+)
+
+//aggregatedRoutes accept all engines and merge their results
+var aggregatedRoutes = map[string]bool{
+	"/api/containers":     true,
+	"/api/containers/all": true,
+}
+
+//selectEngine resolves the engine from the path segment or the engine query parameter,
+//the default engine is used when none is given
+func (h Handler) selectEngine(ctx *gin.Context) {
+	name := ctx.Param("engine")
+	if name == "" {
+		name = ctx.Query("engine")
+	}
+
+	if name == application.AllEngines {
+		if !aggregatedRoutes[apiRoute(ctx.FullPath())] {
+			Error(ctx, http.StatusBadRequest, nil, "all engines are supported only by container lists")
+			return
+		}
+		ctx.Set(engineKey, name)
+		ctx.Next()
+		return
+	}
+
+	service, err := h.Engines.Get(name)
+	if err != nil {
+		Fail(ctx, err)
+		return
+	}
+
+	ctx.Set(engineKey, name)
+	ctx.Set(dockerServiceKey, service)
+	ctx.Next()
+}
+
+//selectedEngine returns the engine name of the request, empty for the default one
+func selectedEngine(ctx *gin.Context) string {
+	return ctx.GetString(engineKey)
+}
+
+//dockerService returns the docker service of the selected engine
+func (h Handler) dockerService(ctx *gin.Context) domain.DockerService {
+	if service, ok := ctx.Get(dockerServiceKey); ok {
+		return service.(domain.DockerService)
+	}
+
+	return h.Engines.Default()
+}
+
+//region Engine Handlers
+
+// getEngines godoc
+// @Summary Retrieves names of the docker engines, the first one is the default
+// @Description /api/v2 responds in the envelope, data.engines holds engine names
+// @Produce json
+// @Success 200 {array} string
+// @Router /engines [get]
+func (h Handler) getEngines(ctx *gin.Context) {
+	type payload struct {
+		Engines []string `json:"engines"`
+	}
+
+	Ok(ctx, payload{Engines: h.Engines.Names()})
+}
+
+//endregion
@@ -0,0 +1,121 @@
+package interfaces
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	apiVersionKey = "apiVersion"
+	apiPrefix     = "/api"
+	apiV2Prefix   = "/api/v2"
+)
+
+//Envelope is the response of the second API version,
+//Data is null when the request failed and Errors lists failures of the request or of its parts
+type Envelope struct {
+	Data   interface{}     `json:"data"`
+	Meta   Meta            `json:"meta"`
+	Errors []ErrorResponse `json:"errors,omitempty"`
+}
+
+//Meta describes the request the envelope responds to
+type Meta struct {
+	RequestID string    `json:"requestId"`
+	Engine    string    `json:"engine,omitempty"`
+	Time      time.Time `json:"time"`
+}
+
+//apiVersion marks requests of the group with the version of the API
+func apiVersion(version int) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Set(apiVersionKey, version)
+		ctx.Next()
+	}
+}
+
+//versionOf returns the API version of the request, the first one when the group has none
+func versionOf(ctx *gin.Context) int {
+	if version := ctx.GetInt(apiVersionKey); version > 0 {
+		return version
+	}
+
+	return 1
+}
+
+//apiRoute returns the first version route of the path without the engine segment,
+//so routes of all versions and engines are looked up in the same tables
+func apiRoute(path string) string {
+	if strings.HasPrefix(path, apiV2Prefix+"/") {
+		path = apiPrefix + strings.TrimPrefix(path, apiV2Prefix)
+	}
+
+	return strings.Replace(path, engineRoutePath, apiPrefix, 1)
+}
+
+func newEnvelope(ctx *gin.Context, data interface{}, errs []ErrorResponse) Envelope {
+	return Envelope{
+		Data: data,
+		Meta: Meta{
+			RequestID: ctx.GetString(requestIDKey),
+			Engine:    selectedEngine(ctx),
+			Time:      time.Now(),
+		},
+		Errors: errs,
+	}
+}
+
+//respond writes data in the envelope along with errors of the parts which failed
+func respond(ctx *gin.Context, data interface{}, errs ...ErrorResponse) {
+	ctx.JSON(http.StatusOK, newEnvelope(ctx, data, errs))
+}
+
+//engineErrors returns errors of the engines ordered by name
+func engineErrors(ctx *gin.Context, errs map[string]error) []ErrorResponse {
+	return partErrors(ctx, errs, func(e *ErrorResponse, engine string) {
+		e.Engine = engine
+	})
+}
+
+//containerErrors returns errors of the containers ordered by id
+func containerErrors(ctx *gin.Context, errs map[string]error) []ErrorResponse {
+	return partErrors(ctx, errs, func(e *ErrorResponse, id string) {
+		e.Container = id
+	})
+}
+
+//partErrors returns errors of the parts of the request ordered by part, name sets the part on the error
+func partErrors(ctx *gin.Context, errs map[string]error, name func(*ErrorResponse, string)) []ErrorResponse {
+	parts := make([]string, 0, len(errs))
+	for part := range errs {
+		parts = append(parts, part)
+	}
+	sort.Strings(parts)
+
+	var result []ErrorResponse
+	for _, part := range parts {
+		e := ErrorResponse{
+			Code:      errorCode(errorStatus(errs[part])),
+			Message:   errs[part].Error(),
+			RequestID: ctx.GetString(requestIDKey),
+		}
+		name(&e, part)
+		result = append(result, e)
+	}
+
+	return result
+}
+
+//errorMessages returns messages of the errors by part for the first API version
+func errorMessages(errs map[string]error) map[string]string {
+	result := make(map[string]string, len(errs))
+	for part, err := range errs {
+		result[part] = err.Error()
+	}
+
+	return result
+}
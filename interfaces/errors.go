@@ -0,0 +1,93 @@
+package interfaces
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"godtop/domain"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	requestIDKey    = "requestId"
+	requestIDHeader = "X-Request-Id"
+
+	internalErrorCode = "internal"
+)
+
+//requestIDPattern limits ids sent by clients to characters which are safe to echo and log
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+//errorKinds map domain error kinds to status codes and codes of the error response
+var errorKinds = []struct {
+	kind   error
+	status int
+	code   string
+}{
+	{domain.ErrNotFound, http.StatusNotFound, "not_found"},
+	{domain.ErrInvalidArgument, http.StatusBadRequest, "invalid_argument"},
+	{domain.ErrForbidden, http.StatusForbidden, "forbidden"},
+	{domain.ErrConflict, http.StatusConflict, "conflict"},
+	{domain.ErrTimeout, http.StatusGatewayTimeout, "timeout"},
+	{domain.ErrUnavailable, http.StatusServiceUnavailable, "unavailable"},
+}
+
+//handleErrors tags the request with an id, taken from X-Request-Id when the client sends a valid one,
+//and responds with the error passed to Fail
+func handleErrors(ctx *gin.Context) {
+	id := ctx.GetHeader(requestIDHeader)
+	if !requestIDPattern.MatchString(id) {
+		id = newRequestID()
+	}
+	ctx.Set(requestIDKey, id)
+	ctx.Header(requestIDHeader, id)
+
+	ctx.Next()
+
+	if last := ctx.Errors.Last(); last != nil && !ctx.Writer.Written() {
+		Error(ctx, errorStatus(last.Err), last.Err, last.Err.Error())
+	}
+}
+
+//errorStatus returns the status code of the error kind, unknown errors are internal
+func errorStatus(err error) int {
+	for _, kind := range errorKinds {
+		if errors.Is(err, kind.kind) {
+			return kind.status
+		}
+	}
+
+	return http.StatusInternalServerError
+}
+
+//errorCode returns the code of the status, statuses without a kind are named after their text in snake case,
+//like request_entity_too_large, and unknown statuses are internal
+func errorCode(status int) string {
+	for _, kind := range errorKinds {
+		if kind.status == status {
+			return kind.code
+		}
+	}
+
+	switch text := http.StatusText(status); {
+	case status == http.StatusUnauthorized:
+		return "unauthenticated"
+	case status == http.StatusInternalServerError || text == "":
+		return internalErrorCode
+	default:
+		return strings.NewReplacer(" ", "_", "-", "_", "'", "").Replace(strings.ToLower(text))
+	}
+}
+
+func newRequestID() string {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return ""
+	}
+
+	return hex.EncodeToString(id)
+}
@@ -0,0 +1,51 @@
+package interfaces
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestHandleErrorsRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(handleErrors)
+	router.GET("/", func(ctx *gin.Context) {
+		ctx.Status(http.StatusNoContent)
+	})
+
+	tests := []struct {
+		header string
+		echoed bool
+	}{
+		{"", false},
+		{"4f2c9a1b", true},
+		{"trace-1.span_2", true},
+		{strings.Repeat("a", 64), true},
+		{strings.Repeat("a", 65), false},
+		{"id with spaces", false},
+		{"id\\nforged: log line", false},
+		{"<script>", false},
+	}
+
+	for _, test := range tests {
+		request := httptest.NewRequest(http.MethodGet, "/", nil)
+		if test.header != "" {
+			request.Header.Set(requestIDHeader, test.header)
+		}
+		recorder := httptest.NewRecorder()
+
+		router.ServeHTTP(recorder, request)
+
+		id := recorder.Header().Get(requestIDHeader)
+		if (id == test.header) != test.echoed {
+			t.Errorf("request id %q is answered with %q, echoed = %v", test.header, id, test.echoed)
+		}
+		if !requestIDPattern.MatchString(id) {
+			t.Errorf("request id %q is answered with invalid id %q", test.header, id)
+		}
+	}
+}
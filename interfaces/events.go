@@ -0,0 +1,131 @@
+package interfaces
+
+import (
+	"fmt"
+	"godtop/application"
+	"godtop/domain"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+//region Event Handlers
+
+// getEvents godoc
+// @Summary Retrieves recorded lifecycle events of containers of every engine
+// @Description /api/v2 responds in the envelope, data.events holds domain.ContainerEvent list
+// @Produce json
+// @Param engine query string false "engine name, events of every engine when not set"
+// @Param container query string false "container Id"
+// @Param name query string false "container Name"
+// @Param action query string false "comma separated actions, e.g. die,oom"
+// @Param since query string false "RFC3339 or unix seconds"
+// @Param until query string false "RFC3339 or unix seconds"
+// @Param limit query int false "return only the latest events"
+// @Success 200 {array} domain.ContainerEvent
+// @Router /events [get]
+func (h Handler) getEvents(ctx *gin.Context) {
+	filter, err := parseEventFilter(ctx)
+	if err != nil {
+		Error(ctx, http.StatusBadRequest, err, err.Error())
+		return
+	}
+
+	type payload struct {
+		Events []domain.ContainerEvent `json:"events"`
+	}
+
+	Ok(ctx, payload{Events: h.EventJournal.Query(filter)})
+}
+
+// streamEvents godoc
+// @Summary Streams live lifecycle events of containers of every engine as Server-Sent Events
+// @Description /api/v2 sends the same events which are not enveloped
+// @Produce text/event-stream
+// @Param engine query string false "engine name, events of every engine when not set"
+// @Param container query string false "container Id"
+// @Param name query string false "container Name"
+// @Param action query string false "comma separated actions, e.g. die,oom"
+// @Success 200 {object} domain.ContainerEvent
+// @Router /events/stream [get]
+func (h Handler) streamEvents(ctx *gin.Context) {
+	filter, err := parseEventFilter(ctx)
+	if err != nil {
+		Error(ctx, http.StatusBadRequest, err, err.Error())
+		return
+	}
+
+	events := h.EventJournal.Subscribe(ctx.Request.Context(), filter)
+
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("X-Accel-Buffering", "no")
+	ctx.Stream(func(w io.Writer) bool {
+		event, ok := <-events
+		if !ok {
+			return false
+		}
+
+		ctx.SSEvent(event.Action, event)
+		return true
+	})
+}
+
+// getContainerTimeline godoc
+// @Summary Retrieves recorded lifecycle events of a container
+// @Description /api/v2 responds in the envelope, data.timeline holds domain.ContainerEvent list
+// @Produce json
+// @Param nameOrId path string true "container Name or Id"
+// @Param engine query string false "engine of the container, the default one when not set"
+// @Success 200 {array} domain.ContainerEvent
+// @Router /container/{nameOrId}/timeline [get]
+func (h Handler) getContainerTimeline(ctx *gin.Context) {
+	nameOrId := ctx.Param("nameOrId")
+
+	timeline, err := h.EventJournal.Timeline(ctx, selectedEngine(ctx), nameOrId)
+	if err != nil {
+		Fail(ctx, err)
+		return
+	}
+
+	type payload struct {
+		Timeline []domain.ContainerEvent `json:"timeline"`
+	}
+
+	Ok(ctx, payload{Timeline: timeline})
+}
+
+//endregion
+
+func parseEventFilter(ctx *gin.Context) (application.EventFilter, error) {
+	filter := application.EventFilter{
+		Engine:      selectedEngine(ctx),
+		ContainerID: ctx.Query("container"),
+		Name:        ctx.Query("name"),
+	}
+
+	if value := ctx.Query("action"); value != "" {
+		filter.Actions = strings.Split(value, ",")
+	}
+
+	var err error
+	if value := ctx.Query("since"); value != "" {
+		if filter.Since, err = parseTime(value); err != nil {
+			return filter, fmt.Errorf("invalid since: %w", err)
+		}
+	}
+	if value := ctx.Query("until"); value != "" {
+		if filter.Until, err = parseTime(value); err != nil {
+			return filter, fmt.Errorf("invalid until: %w", err)
+		}
+	}
+	if value := ctx.Query("limit"); value != "" {
+		if filter.Limit, err = strconv.Atoi(value); err != nil || filter.Limit < 0 {
+			return filter, fmt.Errorf("invalid limit: %s", value)
+		}
+	}
+
+	return filter, nil
+}
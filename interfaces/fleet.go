@@ -0,0 +1,103 @@
+package interfaces
+
+import (
+	"godtop/domain"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+//maxReportSize bounds snapshots pushed by agents
+const maxReportSize = 8 << 20
+
+//region Fleet Handlers
+
+// ingestReport godoc
+// @Summary Registers a snapshot pushed by a godtop agent, a node name belongs to the principal which reported it first
+// @Description /api/v2 responds in the envelope, data holds domain.Node
+// @Accept json
+// @Produce json
+// @Param report body domain.NodeReport true "node snapshot"
+// @Success 200 {object} interfaces.legacyNode
+// @Router /fleet/ingest [post]
+func (h Handler) ingestReport(ctx *gin.Context) {
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxReportSize)
+
+	var report domain.NodeReport
+	if err := ctx.ShouldBindJSON(&report); err != nil {
+		Error(ctx, http.StatusBadRequest, err, err.Error())
+		return
+	}
+
+	if err := h.Fleet.Ingest(report, ctx.ClientIP(), principalOf(ctx).Name); err != nil {
+		Fail(ctx, err)
+		return
+	}
+
+	node, _, err := h.Fleet.Node(report.Node)
+	if err != nil {
+		Fail(ctx, err)
+		return
+	}
+
+	Ok(ctx, nodeView(ctx, node))
+}
+
+// getNodes godoc
+// @Summary Retrieves nodes of the fleet with their host information and status
+// @Description /api/v2 responds in the envelope, data.nodes holds domain.Node list
+// @Produce json
+// @Success 200 {array} interfaces.legacyNode
+// @Router /fleet/nodes [get]
+func (h Handler) getNodes(ctx *gin.Context) {
+	type payload struct {
+		Nodes interface{} `json:"nodes"`
+	}
+
+	Ok(ctx, payload{Nodes: nodesView(ctx, h.Fleet.Nodes())})
+}
+
+// getNode godoc
+// @Summary Retrieves a node of the fleet with its latest snapshot
+// @Description /api/v2 responds in the envelope, data holds domain.Node and domain.MetricsSnapshot
+// @Produce json
+// @Param node path string true "node name"
+// @Success 200 {object} interfaces.legacyMetricsSnapshot
+// @Router /fleet/nodes/{node} [get]
+func (h Handler) getNode(ctx *gin.Context) {
+	node, snapshot, err := h.Fleet.Node(ctx.Param("node"))
+	if err != nil {
+		Fail(ctx, err)
+		return
+	}
+
+	type payload struct {
+		Node     interface{} `json:"node"`
+		Snapshot interface{} `json:"snapshot"`
+	}
+
+	Ok(ctx, payload{Node: nodeView(ctx, node), Snapshot: snapshotView(ctx, snapshot)})
+}
+
+// getFleetContainers godoc
+// @Summary Retrieves containers of all nodes with their latest statistics
+// @Description /api/v2 responds in the envelope, data.containers holds domain.ContainerSnapshot list
+// @Produce json
+// @Param stale query bool false "include containers of stale nodes"
+// @Success 200 {array} interfaces.legacyContainerSnapshot
+// @Router /fleet/containers [get]
+func (h Handler) getFleetContainers(ctx *gin.Context) {
+	includeStale, err := parseBoolQuery(ctx, "stale")
+	if err != nil {
+		Error(ctx, http.StatusBadRequest, err, err.Error())
+		return
+	}
+
+	type payload struct {
+		Containers interface{} `json:"containers"`
+	}
+
+	Ok(ctx, payload{Containers: containerSnapshotsView(ctx, h.Fleet.Containers(includeStale))})
+}
+
+//endregion
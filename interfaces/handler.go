@@ -1,12 +1,15 @@
 package interfaces
 
 import (
+	"context"
 	"fmt"
 	"godtop/application"
+	"godtop/config"
 	"godtop/domain"
 	"log"
+	"net"
 	"net/http"
-	"os"
+	"time"
 
 	_ "godtop/docs"
 
@@ -15,86 +18,308 @@ import (
 	"github.com/swaggo/gin-swagger/swaggerFiles"
 )
 
+const (
+	defaultStatsTimeout = 5 * time.Second
+	maxStatsTimeout     = 30 * time.Second
+)
+
+//debugEnabled is set from the Debug setting of the handler when the server starts
+var debugEnabled bool
+
 func logDebug(format string, args ...interface{}) {
-	if env := os.Getenv("GO_SERVER_DEBUG"); len(env) != 0 {
+	if debugEnabled {
 		log.Printf("[DEBUG] "+format+"\n", args...)
 	}
 }
 
-//ErrorResponse is error respose template
+//ErrorResponse is error respose template,
+//Code is stable for clients to match and Detail is the underlying error when Message does not include it,
+//Engine or Container names the part which failed when the request spans several of them
 type ErrorResponse struct {
-	Message string `json:"reason"`
-	Error   error  `json:"-"`
+	Code      string `json:"code"`
+	Message   string `json:"reason"`
+	Detail    string `json:"detail,omitempty"`
+	RequestID string `json:"requestId,omitempty"`
+	Engine    string `json:"engine,omitempty"`
+	Container string `json:"container,omitempty"`
+	Error     error  `json:"-"`
 }
 
 func (e *ErrorResponse) ToString() string {
-	return fmt.Sprintf("reason: %s, error: %s", e.Message, e.Error)
+	return fmt.Sprintf("request: %s, code: %s, reason: %s, error: %s", e.RequestID, e.Code, e.Message, e.Error)
 }
 
 //Error is wrapped Respond when error
 func Error(ctx *gin.Context, code int, err error, msg string) {
 	e := &ErrorResponse{
-		Message: msg,
-		Error:   err,
+		Code:      errorCode(code),
+		Message:   msg,
+		RequestID: ctx.GetString(requestIDKey),
+		Error:     err,
+	}
+	if err != nil && err.Error() != msg {
+		e.Detail = err.Error()
+	}
+
+	if code >= http.StatusInternalServerError {
+		log.Printf("%s", e.ToString())
+	} else {
+		logDebug("%s", e.ToString())
 	}
 
-	logDebug("%s", e.ToString())
+	if versionOf(ctx) >= 2 {
+		ctx.AbortWithStatusJSON(code, newEnvelope(ctx, nil, []ErrorResponse{*e}))
+		return
+	}
 	ctx.AbortWithStatusJSON(code, e)
 }
 
+//Fail aborts the request with the error, the status code is chosen by handleErrors from the error kind
+func Fail(ctx *gin.Context, err error) {
+	ctx.Error(err)
+	ctx.Abort()
+}
+
+//Ok responds with src wrapped in an array in the first API version and in the envelope data since the second one
 func Ok(ctx *gin.Context, src ...interface{}) {
+	if versionOf(ctx) >= 2 {
+		var data interface{} = src
+		if len(src) == 1 {
+			data = src[0]
+		}
+		respond(ctx, data)
+		return
+	}
+
 	ctx.JSON(http.StatusOK, src)
 }
 
 //Handler docker service
 type Handler struct {
-	DockerService domain.DockerService
-	HostService   domain.HostService
+	Engines      *application.Engines
+	HostService  domain.HostService
+	HostSampler  *application.HostSampler
+	MetricsStore domain.MetricsStore
+	Collector    *application.MetricsCollector
+	EventJournal *application.EventJournal
+	AlertEngine  *application.AlertEngine
+	Notifier     *application.Notifier
+	Fleet        *application.Fleet
+
+	//Config is the effective configuration exposed with secrets redacted
+	Config          *config.Config
+	Debug           bool
+	MetricsPath     string
+	ShutdownTimeout time.Duration
+	//ShutdownDelay is how long readiness fails before requests are drained so load balancers stop routing to the server
+	ShutdownDelay time.Duration
+
+	Authenticators []Authenticator
+	AccessPolicy   *application.AccessPolicy
+	TLS            *TLSFiles
+
+	//draining is done once the server is asked to shut down, streams when it drains requests
+	draining context.Context
+	streams  context.Context
 }
 
 //Routes returns the initialized router
-func (h Handler) routes(port string) *gin.Engine {
-	r := gin.Default()
+func (h Handler) routes() *gin.Engine {
+	debugEnabled = h.Debug
+	if !h.Debug {
+		gin.SetMode(gin.ReleaseMode)
+	}
+
+	r := gin.New()
+	r.Use(gin.Recovery())
+
+	//probes are neither logged nor authenticated
+	r.GET("/healthz", h.getLiveness)
+	r.GET("/readyz", h.getReadiness)
+
+	r.Use(gin.LoggerWithFormatter(logFormatter), handleErrors, h.authenticate)
+
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	if h.MetricsPath != "" {
+		r.GET(h.MetricsPath, h.authorize, newMetricsHandler(h.Collector))
+	}
 
-	api := r.Group("/api")
-	{
-		api.GET("/containers", h.getRunningContainers)
-		api.GET("/containers/all", h.getAllContainers)
-		api.GET("/container/:nameOrId", h.getContainer)
-		api.GET("/container/:nameOrId/stats", h.getContainerStats)
-		api.GET("/volumes", h.getVolumes)
-		api.GET("/host", h.getHostInfo)
+	engineRoutes, serverRoutes := h.engineRoutes(), h.serverRoutes()
+	api := r.Group(apiPrefix, h.selectEngine, h.authorize)
+	v2 := r.Group(apiV2Prefix, apiVersion(2), h.selectEngine, h.authorize)
+	for _, group := range []*gin.RouterGroup{api, v2} {
+		h.register(group, engineRoutes)
+		h.register(group.Group("/engines/:engine"), engineRoutes)
+		h.register(group, serverRoutes)
 	}
 
 	return r
 }
 
-//RunServer starts server on a specific port
-func (h Handler) RunServer(port int) error {
-	log.Printf("Server running at http://localhost:%d/", port)
-	portStr := fmt.Sprintf(":%d", port)
-	return h.routes(portStr).Run(portStr)
+//route is an entry of the route table shared by the API versions
+type route struct {
+	method  string
+	path    string
+	handler gin.HandlerFunc
+	//stream routes are closed when the server shuts down
+	stream bool
+}
+
+//register adds the routes to the group
+func (h Handler) register(group *gin.RouterGroup, routes []route) {
+	for _, route := range routes {
+		if route.stream {
+			group.Handle(route.method, route.path, h.closeOnShutdown, route.handler)
+			continue
+		}
+		group.Handle(route.method, route.path, route.handler)
+	}
+}
+
+//engineRoutes are served by the selected docker engine
+func (h Handler) engineRoutes() []route {
+	return []route{
+		{http.MethodGet, "/containers", h.getRunningContainers, false},
+		{http.MethodGet, "/containers/all", h.getAllContainers, false},
+		{http.MethodGet, "/containers/stats", h.getContainersStats, false},
+		{http.MethodGet, "/container/:nameOrId", h.getContainer, false},
+		{http.MethodGet, "/container/:nameOrId/stats", h.getContainerStats, false},
+		{http.MethodGet, "/container/:nameOrId/stats/ws", h.streamContainerStats, true},
+		{http.MethodGet, "/container/:nameOrId/logs", h.getContainerLogs, true},
+		{http.MethodPost, "/container/:nameOrId/start", h.startContainer, false},
+		{http.MethodPost, "/container/:nameOrId/stop", h.stopContainer, false},
+		{http.MethodPost, "/container/:nameOrId/restart", h.restartContainer, false},
+		{http.MethodPost, "/container/:nameOrId/pause", h.pauseContainer, false},
+		{http.MethodPost, "/container/:nameOrId/unpause", h.unpauseContainer, false},
+		{http.MethodPost, "/container/:nameOrId/kill", h.killContainer, false},
+		{http.MethodDelete, "/container/:nameOrId", h.removeContainer, false},
+		{http.MethodGet, "/volumes", h.getVolumes, false},
+	}
+}
+
+//serverRoutes are independent of docker engines
+func (h Handler) serverRoutes() []route {
+	return []route{
+		{http.MethodGet, "/engines", h.getEngines, false},
+		{http.MethodGet, "/health", h.getHealth, false},
+		{http.MethodGet, "/container/:nameOrId/stats/history", h.getContainerStatsHistory, false},
+		{http.MethodGet, "/container/:nameOrId/timeline", h.getContainerTimeline, false},
+		{http.MethodGet, "/events", h.getEvents, false},
+		{http.MethodGet, "/events/stream", h.streamEvents, true},
+		{http.MethodGet, "/alerts", h.getAlerts, false},
+		{http.MethodGet, "/alerts/rules", h.getAlertRules, false},
+		{http.MethodGet, "/notifications", h.getNotifications, false},
+		{http.MethodGet, "/host", h.getHostInfo, false},
+		{http.MethodGet, "/host/events", h.streamHostInfo, true},
+		{http.MethodGet, "/host/history", h.getHostHistory, false},
+		{http.MethodGet, "/config", h.getConfig, false},
+		{http.MethodPost, "/fleet/ingest", h.ingestReport, false},
+		{http.MethodGet, "/fleet/nodes", h.getNodes, false},
+		{http.MethodGet, "/fleet/nodes/:node", h.getNode, false},
+		{http.MethodGet, "/fleet/containers", h.getFleetContainers, false},
+	}
+}
+
+//RunServer serves on the address, over HTTPS when TLS is configured, until the context is done,
+//then fails readiness for ShutdownDelay, stops accepting connections, closes streams
+//and waits for in-flight requests up to ShutdownTimeout
+func (h Handler) RunServer(ctx context.Context, address string) error {
+	draining, drain := context.WithCancel(context.Background())
+	defer drain()
+	h.draining = draining
+	streams, closeStreams := context.WithCancel(context.Background())
+	defer closeStreams()
+	h.streams = streams
+
+	server := &http.Server{
+		Addr:    address,
+		Handler: h.routes(),
+	}
+	server.RegisterOnShutdown(closeStreams)
+
+	scheme := "http"
+	listen := server.ListenAndServe
+	if h.TLS != nil {
+		if err := h.TLS.validate(); err != nil {
+			return err
+		}
+
+		reloader, err := newCertReloader(*h.TLS)
+		if err != nil {
+			return err
+		}
+		go reloader.watchSignals()
+
+		server.TLSConfig = reloader.config()
+		scheme = "https"
+		listen = func() error {
+			return server.ListenAndServeTLS("", "")
+		}
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- listen()
+	}()
+	log.Printf("Server running at %s://%s/", scheme, displayAddress(address))
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+
+	drain()
+	if h.ShutdownDelay > 0 {
+		log.Printf("Server shutting down, failing readiness for %s", h.ShutdownDelay)
+		select {
+		case err := <-errs:
+			return err
+		case <-time.After(h.ShutdownDelay):
+		}
+	}
+
+	log.Printf("Server shutting down, draining requests for up to %s", h.ShutdownTimeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), h.ShutdownTimeout)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		server.Close()
+		return fmt.Errorf("requests were not drained in time: %w", err)
+	}
+
+	return nil
+}
+
+//displayAddress returns the address with localhost when it listens on all interfaces
+func displayAddress(address string) string {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil || host != "" {
+		return address
+	}
+
+	return net.JoinHostPort("localhost", port)
 }
 
 //region API Handlers
 
 // getContainer godoc
 // @Summary Retrieves container information by its Id or Name
+// @Description /api/v2 responds in the envelope, data holds domain.ContainerDetails
 // @Produce json
 // @Param nameOrId path string true "container Name or Id"
-// @Success 200 {object} domain.Container
+// @Success 200 {object} domain.ContainerDetails
 // @Router /container/{nameOrId} [get]
 func (h Handler) getContainer(ctx *gin.Context) {
 	nameOrId := ctx.Param("nameOrId")
 
 	interactor := application.ContainerInteractor{
-		Service: h.DockerService,
+		Service: h.dockerService(ctx),
 	}
 
-	container, err := interactor.Get(ctx, nameOrId)
+	container, err := interactor.Inspect(ctx, nameOrId)
 	if err != nil {
-		Error(ctx, http.StatusNotFound, err, err.Error())
+		Fail(ctx, err)
 		return
 	}
 
@@ -103,7 +328,9 @@ func (h Handler) getContainer(ctx *gin.Context) {
 
 // getRunningContainers godoc
 // @Summary Retrieves running containers
+// @Description /api/v2 responds in the envelope, data.containers holds domain.Container list and errors lists engines which failed when all engines are selected
 // @Produce json
+// @Param engine query string false "engine name, * merges containers of all engines"
 // @Success 200 {array} domain.Container
 // @Router /containers [get]
 func (h Handler) getRunningContainers(ctx *gin.Context) {
@@ -112,15 +339,37 @@ func (h Handler) getRunningContainers(ctx *gin.Context) {
 
 // getAllContainers godoc
 // @Summary Retrieves all containers
+// @Description /api/v2 responds in the envelope, data.containers holds domain.Container list and errors lists engines which failed when all engines are selected
 // @Produce json
+// @Param engine query string false "engine name, * merges containers of all engines"
 // @Success 200 {array} domain.Container
 func (h Handler) getAllContainers(ctx *gin.Context) {
 	h.getContainers(ctx, true)
 }
 
 func (h Handler) getContainers(ctx *gin.Context, all bool) {
+	if selectedEngine(ctx) == application.AllEngines {
+		interactor := application.EnginesInteractor{
+			Engines: h.Engines,
+		}
+
+		containers, errs := interactor.GetContainers(ctx, all)
+
+		type payload struct {
+			Containers []domain.Container `json:"containers"`
+			Errors     map[string]string  `json:"errors,omitempty"`
+		}
+		if versionOf(ctx) >= 2 {
+			respond(ctx, payload{Containers: containers}, engineErrors(ctx, errs)...)
+			return
+		}
+
+		Ok(ctx, payload{Containers: containers, Errors: errorMessages(errs)})
+		return
+	}
+
 	interactor := application.ContainerInteractor{
-		Service: h.DockerService,
+		Service: h.dockerService(ctx),
 	}
 	var containers *[]domain.Container
 	var err error
@@ -132,7 +381,7 @@ func (h Handler) getContainers(ctx *gin.Context, all bool) {
 	}
 
 	if err != nil {
-		Error(ctx, http.StatusNotFound, err, err.Error())
+		Fail(ctx, err)
 		return
 	}
 	type payload struct {
@@ -143,43 +392,99 @@ func (h Handler) getContainers(ctx *gin.Context, all bool) {
 
 // getContainerStats godoc
 // @Summary Retrieves statistics of a container
+// @Description /api/v2 responds in the envelope, data.stats holds domain.ContainerStats
 // @Produce json
 // @Param nameOrId path string true "container Name or Id"
-// @Success 200 {object} domain.ContainerStats
+// @Success 200 {object} interfaces.legacyContainerStats
 // @Router /container/{nameOrId}/stats [get]
 func (h Handler) getContainerStats(ctx *gin.Context) {
 	nameOrId := ctx.Param("nameOrId")
 
 	interactor := application.ContainerInteractor{
-		Service: h.DockerService,
+		Service: h.dockerService(ctx),
 	}
 
-	stats, err := interactor.GetStats(ctx, nameOrId, false)
+	stats, err := interactor.GetStats(ctx, nameOrId)
 	if err != nil {
-		Error(ctx, http.StatusNotFound, err, err.Error())
+		Fail(ctx, err)
 		return
 	}
 
 	type payload struct {
-		Stats *domain.ContainerStats `json:"stats"`
+		Stats interface{} `json:"stats"`
+	}
+
+	Ok(ctx, payload{Stats: statsView(ctx, stats)})
+}
+
+// getContainersStats godoc
+// @Summary Samples statistics of all running containers concurrently, containers which failed are listed in errors by id
+// @Description /api/v2 responds in the envelope, data.stats holds domain.ContainerStats by container id and errors lists containers which failed
+// @Produce json
+// @Param timeout query string false "deadline of the sampling, seconds or duration, 5s by default and at most 30s"
+// @Success 200 {array} interfaces.legacyContainersStats "a single element with statistics and errors by container id"
+// @Router /containers/stats [get]
+func (h Handler) getContainersStats(ctx *gin.Context) {
+	timeout, err := parseDurationQuery(ctx, "timeout")
+	if err != nil {
+		Error(ctx, http.StatusBadRequest, err, err.Error())
+		return
+	}
+	deadline := defaultStatsTimeout
+	if timeout != nil {
+		deadline = *timeout
+	}
+	if deadline <= 0 || deadline > maxStatsTimeout {
+		Error(ctx, http.StatusBadRequest, nil, "timeout must be positive and at most "+maxStatsTimeout.String())
+		return
+	}
+
+	interactor := application.ContainerInteractor{
+		Service: h.dockerService(ctx),
+	}
+
+	statsCtx, cancel := context.WithTimeout(ctx.Request.Context(), deadline)
+	defer cancel()
+
+	stats, errs, err := interactor.GetRunningStats(statsCtx)
+	if err != nil {
+		Fail(ctx, err)
+		return
+	}
+
+	if versionOf(ctx) >= 2 {
+		type payload struct {
+			Stats map[string]*domain.ContainerStats `json:"stats"`
+		}
+		respond(ctx, payload{Stats: stats}, containerErrors(ctx, errs)...)
+		return
+	}
+
+	legacy := legacyContainersStats{
+		Stats:  make(map[string]*legacyContainerStats, len(stats)),
+		Errors: errorMessages(errs),
+	}
+	for id, item := range stats {
+		legacy.Stats[id] = toLegacyStats(item)
 	}
 
-	Ok(ctx, payload{Stats: stats})
+	Ok(ctx, legacy)
 }
 
 // getVolumes godoc
 // @Summary Retrieves mounted and created volumes
+// @Description /api/v2 responds in the envelope, data.volumes holds domain.Volume list
 // @Produce json
 // @Success 200 {array} domain.Volume
 // @Router /volumes [get]
 func (h Handler) getVolumes(ctx *gin.Context) {
 	interactor := application.VolumeInteractor{
-		Service: h.DockerService,
+		Service: h.dockerService(ctx),
 	}
 
 	volumes, err := interactor.GetAll(ctx)
 	if err != nil {
-		Error(ctx, http.StatusNotFound, err, err.Error())
+		Fail(ctx, err)
 		return
 	}
 
@@ -192,8 +497,9 @@ func (h Handler) getVolumes(ctx *gin.Context) {
 
 // getHostInfo godoc
 // @Summary Retrieves information about host stystem
+// @Description /api/v2 responds in the envelope, data holds domain.HostInfo
 // @Produce json
-// @Success 200 {object} domain.HostInfo
+// @Success 200 {object} interfaces.legacyHostInfo
 // @Router /host [get]
 func (h Handler) getHostInfo(ctx *gin.Context) {
 	interactor := application.HostInteractor{
@@ -202,7 +508,7 @@ func (h Handler) getHostInfo(ctx *gin.Context) {
 
 	info := interactor.GetInfo(ctx)
 
-	Ok(ctx, info)
+	Ok(ctx, hostView(ctx, info))
 }
 
 //endregion
@@ -0,0 +1,40 @@
+package interfaces
+
+import (
+	"godtop/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	healthOk       = "ok"
+	healthDegraded = "degraded"
+)
+
+//region Health Handlers
+
+// getHealth godoc
+// @Summary Retrieves connection status of the docker engines, degraded when any of them is disconnected
+// @Description /api/v2 responds in the envelope, data.engines holds domain.EngineStatus list
+// @Produce json
+// @Success 200 {array} domain.EngineStatus
+// @Router /health [get]
+func (h Handler) getHealth(ctx *gin.Context) {
+	engines := h.Engines.Status()
+
+	status := healthOk
+	for _, engine := range engines {
+		if !engine.Connected {
+			status = healthDegraded
+		}
+	}
+
+	type payload struct {
+		Status  string                `json:"status"`
+		Engines []domain.EngineStatus `json:"engines"`
+	}
+
+	Ok(ctx, payload{Status: status, Engines: engines})
+}
+
+//endregion
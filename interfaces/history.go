@@ -0,0 +1,133 @@
+package interfaces
+
+import (
+	"errors"
+	"fmt"
+	"godtop/application"
+	"godtop/domain"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultHistoryRange = time.Hour
+	maxHistoryBuckets   = 10000
+)
+
+//region History Handlers
+
+// getContainerStatsHistory godoc
+// @Summary Retrieves sampled statistics of a container
+// @Description /api/v2 responds in the envelope, data.history holds domain.MetricsBucket list
+// @Produce json
+// @Param nameOrId path string true "container Name or Id"
+// @Param engine query string false "engine of the container, the default one when not set"
+// @Param from query string false "range start, RFC3339 or unix seconds, defaults to an hour ago"
+// @Param to query string false "range end, RFC3339 or unix seconds, defaults to now"
+// @Param step query string false "downsampling step, e.g. 1m"
+// @Success 200 {array} domain.MetricsBucket
+// @Router /container/{nameOrId}/stats/history [get]
+func (h Handler) getContainerStatsHistory(ctx *gin.Context) {
+	nameOrId := ctx.Param("nameOrId")
+
+	from, to, step, err := parseHistoryQuery(ctx)
+	if err != nil {
+		Error(ctx, http.StatusBadRequest, err, err.Error())
+		return
+	}
+
+	interactor := application.MetricsInteractor{
+		Store:   h.MetricsStore,
+		Service: h.dockerService(ctx),
+	}
+
+	history, err := interactor.GetContainerHistory(ctx, nameOrId, from, to, step)
+	if err != nil {
+		Fail(ctx, err)
+		return
+	}
+
+	type payload struct {
+		History []domain.MetricsBucket `json:"history"`
+	}
+
+	Ok(ctx, payload{History: history})
+}
+
+// getHostHistory godoc
+// @Summary Retrieves sampled information about host system
+// @Description /api/v2 responds in the envelope, data.history holds domain.MetricsBucket list
+// @Produce json
+// @Param from query string false "range start, RFC3339 or unix seconds, defaults to an hour ago"
+// @Param to query string false "range end, RFC3339 or unix seconds, defaults to now"
+// @Param step query string false "downsampling step, e.g. 1m"
+// @Success 200 {array} domain.MetricsBucket
+// @Router /host/history [get]
+func (h Handler) getHostHistory(ctx *gin.Context) {
+	from, to, step, err := parseHistoryQuery(ctx)
+	if err != nil {
+		Error(ctx, http.StatusBadRequest, err, err.Error())
+		return
+	}
+
+	interactor := application.MetricsInteractor{
+		Store: h.MetricsStore,
+	}
+
+	history, err := interactor.GetHostHistory(ctx, from, to, step)
+	if err != nil {
+		Fail(ctx, err)
+		return
+	}
+
+	type payload struct {
+		History []domain.MetricsBucket `json:"history"`
+	}
+
+	Ok(ctx, payload{History: history})
+}
+
+//endregion
+
+func parseHistoryQuery(ctx *gin.Context) (from time.Time, to time.Time, step time.Duration, err error) {
+	to = time.Now()
+	if value := ctx.Query("to"); value != "" {
+		if to, err = parseTime(value); err != nil {
+			return from, to, step, fmt.Errorf("invalid to: %w", err)
+		}
+	}
+
+	from = to.Add(-defaultHistoryRange)
+	if value := ctx.Query("from"); value != "" {
+		if from, err = parseTime(value); err != nil {
+			return from, to, step, fmt.Errorf("invalid from: %w", err)
+		}
+	}
+
+	if !from.Before(to) {
+		return from, to, step, errors.New("from must be before to")
+	}
+
+	if value := ctx.Query("step"); value != "" {
+		if step, err = time.ParseDuration(value); err != nil {
+			return from, to, step, fmt.Errorf("invalid step: %w", err)
+		}
+		if step <= 0 || to.Sub(from)/step > maxHistoryBuckets {
+			return from, to, step, fmt.Errorf("step must be positive and produce at most %d buckets", maxHistoryBuckets)
+		}
+	}
+
+	return from, to, step, nil
+}
+
+//parseTime accepts RFC3339 or unix seconds
+func parseTime(value string) (time.Time, error) {
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(seconds, 0), nil
+	}
+
+	return time.Parse(time.RFC3339, value)
+}
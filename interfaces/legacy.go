@@ -0,0 +1,165 @@
+package interfaces
+
+import (
+	"godtop/domain"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+//legacyContainerStats keeps PascalCase names of container statistics in the first API version,
+//statistics added later are served by the second version only
+type legacyContainerStats struct {
+	RxBytes     int64
+	TxBytes     int64
+	UsedMemory  int64
+	MemoryUsage float32
+	CpuUsage    float32
+}
+
+//legacyContainersStats is the response of bulk statistics in the first API version,
+//statistics and messages of containers which failed are keyed by container id
+type legacyContainersStats struct {
+	Stats  map[string]*legacyContainerStats `json:"stats"`
+	Errors map[string]string                `json:"errors,omitempty"`
+}
+
+//legacyHostInfo keeps PascalCase names of host information in the first API version
+type legacyHostInfo struct {
+	CpuUsage        float64
+	UsedSwapMemory  uint64
+	TotalSwapMemory uint64
+	UsedMemory      uint64
+	TotalMemory     uint64
+	UsedStorage     uint64
+	TotalStorage    uint64
+}
+
+type legacyNode struct {
+	Name       string          `json:"name"`
+	Address    string          `json:"address"`
+	Status     string          `json:"status"`
+	LastSeen   time.Time       `json:"lastSeen"`
+	Containers int             `json:"containers"`
+	Running    int             `json:"running"`
+	Host       *legacyHostInfo `json:"host"`
+}
+
+type legacyContainerSnapshot struct {
+	Container domain.Container      `json:"container"`
+	Stats     *legacyContainerStats `json:"stats,omitempty"`
+}
+
+type legacyMetricsSnapshot struct {
+	Time       time.Time                 `json:"time"`
+	Host       *legacyHostInfo           `json:"host"`
+	Containers []legacyContainerSnapshot `json:"containers"`
+}
+
+//statsView returns container statistics as the API version of the request serves them
+func statsView(ctx *gin.Context, stats *domain.ContainerStats) interface{} {
+	if versionOf(ctx) >= 2 {
+		return stats
+	}
+
+	return toLegacyStats(stats)
+}
+
+//hostView returns host information as the API version of the request serves it
+func hostView(ctx *gin.Context, info *domain.HostInfo) interface{} {
+	if versionOf(ctx) >= 2 {
+		return info
+	}
+
+	return toLegacyHost(info)
+}
+
+func nodeView(ctx *gin.Context, node *domain.Node) interface{} {
+	if versionOf(ctx) >= 2 || node == nil {
+		return node
+	}
+
+	return toLegacyNode(*node)
+}
+
+func nodesView(ctx *gin.Context, nodes []domain.Node) interface{} {
+	if versionOf(ctx) >= 2 {
+		return nodes
+	}
+
+	result := make([]legacyNode, len(nodes))
+	for i, node := range nodes {
+		result[i] = toLegacyNode(node)
+	}
+
+	return result
+}
+
+func snapshotView(ctx *gin.Context, snapshot *domain.MetricsSnapshot) interface{} {
+	if versionOf(ctx) >= 2 || snapshot == nil {
+		return snapshot
+	}
+
+	return legacyMetricsSnapshot{
+		Time:       snapshot.Time,
+		Host:       toLegacyHost(snapshot.Host),
+		Containers: toLegacySnapshots(snapshot.Containers),
+	}
+}
+
+func containerSnapshotsView(ctx *gin.Context, snapshots []domain.ContainerSnapshot) interface{} {
+	if versionOf(ctx) >= 2 {
+		return snapshots
+	}
+
+	return toLegacySnapshots(snapshots)
+}
+
+func toLegacyNode(node domain.Node) legacyNode {
+	return legacyNode{
+		Name:       node.Name,
+		Address:    node.Address,
+		Status:     node.Status,
+		LastSeen:   node.LastSeen,
+		Containers: node.Containers,
+		Running:    node.Running,
+		Host:       toLegacyHost(node.Host),
+	}
+}
+
+func toLegacyStats(stats *domain.ContainerStats) *legacyContainerStats {
+	if stats == nil {
+		return nil
+	}
+
+	return &legacyContainerStats{
+		RxBytes:     stats.RxBytes,
+		TxBytes:     stats.TxBytes,
+		UsedMemory:  stats.UsedMemory,
+		MemoryUsage: stats.MemoryUsage,
+		CpuUsage:    stats.CpuUsage,
+	}
+}
+
+func toLegacyHost(info *domain.HostInfo) *legacyHostInfo {
+	if info == nil {
+		return nil
+	}
+
+	legacy := legacyHostInfo(*info)
+	return &legacy
+}
+
+//toLegacySnapshots keeps nil snapshots nil since it means containers could not be listed
+func toLegacySnapshots(snapshots []domain.ContainerSnapshot) []legacyContainerSnapshot {
+	if snapshots == nil {
+		return nil
+	}
+
+	result := make([]legacyContainerSnapshot, len(snapshots))
+	for i, snapshot := range snapshots {
+		result[i] = legacyContainerSnapshot{Container: snapshot.Container, Stats: toLegacyStats(snapshot.Stats)}
+	}
+
+	return result
+}
@@ -0,0 +1,136 @@
+package interfaces
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"godtop/application"
+	"godtop/domain"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	defaultLogTail = "100"
+	//maxLogTail bounds lines of logs which are not followed since they are buffered before the response
+	maxLogTail = 10000
+)
+
+//region Log Handlers
+
+// getContainerLogs godoc
+// @Summary Retrieves logs of a container, follow streams them as JSON lines or over WebSocket
+// @Description /api/v2 responds in the envelope, data.logs holds domain.LogEntry list, followed logs are not enveloped
+// @Produce json
+// @Param nameOrId path string true "container Name or Id"
+// @Param tail query string false "number of lines from the end of logs, 100 by default, all or more than 10000 only when logs are streamed"
+// @Param since query string false "show logs since timestamp or relative duration, e.g. 10m"
+// @Param until query string false "show logs before timestamp or relative duration, e.g. 10m"
+// @Param timestamps query bool false "parse timestamps of log lines"
+// @Param follow query bool false "keep streaming new log lines"
+// @Success 200 {array} domain.LogEntry
+// @Router /container/{nameOrId}/logs [get]
+func (h Handler) getContainerLogs(ctx *gin.Context) {
+	nameOrId := ctx.Param("nameOrId")
+
+	options := domain.LogOptions{
+		Tail:  ctx.DefaultQuery("tail", defaultLogTail),
+		Since: ctx.Query("since"),
+		Until: ctx.Query("until"),
+	}
+
+	var err error
+	if options.Timestamps, err = parseBoolQuery(ctx, "timestamps"); err != nil {
+		Error(ctx, http.StatusBadRequest, err, err.Error())
+		return
+	}
+	if options.Follow, err = parseBoolQuery(ctx, "follow"); err != nil {
+		Error(ctx, http.StatusBadRequest, err, err.Error())
+		return
+	}
+	if !options.Follow && !websocket.IsWebSocketUpgrade(ctx.Request) {
+		if lines, err := strconv.Atoi(options.Tail); err != nil || lines < 0 || lines > maxLogTail {
+			Error(ctx, http.StatusBadRequest, err, fmt.Sprintf("tail must be a number of lines up to %d unless logs are streamed", maxLogTail))
+			return
+		}
+	}
+
+	interactor := application.LogInteractor{
+		Service: h.dockerService(ctx),
+	}
+
+	logsCtx, cancel := context.WithCancel(ctx.Request.Context())
+	defer cancel()
+
+	entries, err := interactor.Get(logsCtx, nameOrId, options)
+	if err != nil {
+		Fail(ctx, err)
+		return
+	}
+
+	switch {
+	case websocket.IsWebSocketUpgrade(ctx.Request):
+		streamLogsWebSocket(ctx, entries, cancel)
+	case options.Follow:
+		streamLogsChunked(ctx, entries)
+	default:
+		logs := []domain.LogEntry{}
+		for entry := range entries {
+			logs = append(logs, entry)
+		}
+
+		type payload struct {
+			Logs []domain.LogEntry `json:"logs"`
+		}
+
+		Ok(ctx, payload{Logs: logs})
+	}
+}
+
+//endregion
+
+//streamLogsChunked writes every entry as a JSON line flushed to the client
+func streamLogsChunked(ctx *gin.Context, entries <-chan domain.LogEntry) {
+	ctx.Header("Content-Type", "application/x-ndjson")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("X-Accel-Buffering", "no")
+	ctx.Status(http.StatusOK)
+
+	encoder := json.NewEncoder(ctx.Writer)
+	ctx.Stream(func(w io.Writer) bool {
+		entry, ok := <-entries
+		if !ok {
+			return false
+		}
+
+		return encoder.Encode(entry) == nil
+	})
+}
+
+func streamLogsWebSocket(ctx *gin.Context, entries <-chan domain.LogEntry, cancel context.CancelFunc) {
+	conn, err := upgrader.Upgrade(ctx.Writer, ctx.Request, nil)
+	if err != nil {
+		logDebug("websocket upgrade failed: %s", err)
+		return
+	}
+	defer conn.Close()
+
+	go discardIncoming(conn, cancel)
+
+	for entry := range entries {
+		conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+		if err := conn.WriteJSON(entry); err != nil {
+			logDebug("websocket write failed: %s", err)
+			return
+		}
+	}
+
+	conn.WriteControl(websocket.CloseMessage,
+		websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+		time.Now().Add(wsWriteTimeout))
+}
@@ -0,0 +1,230 @@
+package interfaces
+
+import (
+	"godtop/application"
+	"godtop/domain"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+//runningState is the state of containers whose statistics are sampled
+const runningState = "running"
+
+var (
+	containerLabels = []string{"id", "name", "engine"}
+
+	containerCpuUsageDesc = prometheus.NewDesc("godtop_container_cpu_usage_percent",
+		"CPU usage of a container in percent", containerLabels, nil)
+	containerCpuUserDesc = prometheus.NewDesc("godtop_container_cpu_user_percent",
+		"CPU usage of a container in user mode in percent", containerLabels, nil)
+	containerCpuKernelDesc = prometheus.NewDesc("godtop_container_cpu_kernel_percent",
+		"CPU usage of a container in kernel mode in percent", containerLabels, nil)
+	containerCpuCoreDesc = prometheus.NewDesc("godtop_container_cpu_core_usage_percent",
+		"CPU usage of a container on a core in percent, not reported on cgroup v2 hosts", append(containerLabels, "core"), nil)
+	containerCpuQuotaDesc = prometheus.NewDesc("godtop_container_cpu_quota",
+		"CPUs a container may use, not exported when unlimited", containerLabels, nil)
+	containerCpuQuotaUsageDesc = prometheus.NewDesc("godtop_container_cpu_quota_usage_percent",
+		"CPU usage of a container in percent of its quota or of all CPUs when unlimited", containerLabels, nil)
+	containerCpuPeriodsDesc = prometheus.NewDesc("godtop_container_cpu_periods_total",
+		"Enforcement periods of the CPU quota of a container", containerLabels, nil)
+	containerCpuThrottledPeriodsDesc = prometheus.NewDesc("godtop_container_cpu_throttled_periods_total",
+		"Periods a container was throttled in", containerLabels, nil)
+	containerCpuThrottledSecondsDesc = prometheus.NewDesc("godtop_container_cpu_throttled_seconds_total",
+		"Time a container was throttled for", containerLabels, nil)
+	containerUsedMemoryDesc = prometheus.NewDesc("godtop_container_memory_used_bytes",
+		"Memory used by a container without inactive page cache", containerLabels, nil)
+	containerMemoryUsageDesc = prometheus.NewDesc("godtop_container_memory_usage_percent",
+		"Memory usage of a container in percent of its limit", containerLabels, nil)
+	containerMemoryRSSDesc = prometheus.NewDesc("godtop_container_memory_rss_bytes",
+		"Anonymous memory of a container", containerLabels, nil)
+	containerMemoryCacheDesc = prometheus.NewDesc("godtop_container_memory_cache_bytes",
+		"Page cache memory of a container", containerLabels, nil)
+	containerMemorySwapDesc = prometheus.NewDesc("godtop_container_memory_swap_bytes",
+		"Swap used by a container, not reported on cgroup v2 hosts", containerLabels, nil)
+	containerMemoryLimitDesc = prometheus.NewDesc("godtop_container_memory_limit_bytes",
+		"Memory limit of a container, not exported when unlimited", containerLabels, nil)
+	containerRxBytesDesc = prometheus.NewDesc("godtop_container_network_receive_bytes_total",
+		"Bytes received by a container", containerLabels, nil)
+	containerTxBytesDesc = prometheus.NewDesc("godtop_container_network_transmit_bytes_total",
+		"Bytes transmitted by a container", containerLabels, nil)
+	containerBlockReadBytesDesc = prometheus.NewDesc("godtop_container_blkio_read_bytes_total",
+		"Bytes read by a container from block devices", containerLabels, nil)
+	containerBlockWriteBytesDesc = prometheus.NewDesc("godtop_container_blkio_write_bytes_total",
+		"Bytes written by a container to block devices", containerLabels, nil)
+	containerBlockReadOpsDesc = prometheus.NewDesc("godtop_container_blkio_read_operations_total",
+		"Read operations of a container on block devices, not reported on cgroup v2 hosts", containerLabels, nil)
+	containerBlockWriteOpsDesc = prometheus.NewDesc("godtop_container_blkio_write_operations_total",
+		"Write operations of a container on block devices, not reported on cgroup v2 hosts", containerLabels, nil)
+	containerPidsDesc = prometheus.NewDesc("godtop_container_pids",
+		"Processes and threads running in a container", containerLabels, nil)
+	containerPidsLimitDesc = prometheus.NewDesc("godtop_container_pids_limit",
+		"Limit of processes and threads of a container, not exported when unlimited", containerLabels, nil)
+
+	//interfaceMetrics are exported for every network interface of a container
+	interfaceMetrics = []struct {
+		desc  *prometheus.Desc
+		value func(domain.NetworkStats) int64
+	}{
+		{interfaceMetricDesc("receive_bytes_total", "Bytes received"), func(n domain.NetworkStats) int64 { return n.RxBytes }},
+		{interfaceMetricDesc("receive_packets_total", "Packets received"), func(n domain.NetworkStats) int64 { return n.RxPackets }},
+		{interfaceMetricDesc("receive_errors_total", "Receive errors"), func(n domain.NetworkStats) int64 { return n.RxErrors }},
+		{interfaceMetricDesc("receive_packets_dropped_total", "Received packets dropped"), func(n domain.NetworkStats) int64 { return n.RxDropped }},
+		{interfaceMetricDesc("transmit_bytes_total", "Bytes transmitted"), func(n domain.NetworkStats) int64 { return n.TxBytes }},
+		{interfaceMetricDesc("transmit_packets_total", "Packets transmitted"), func(n domain.NetworkStats) int64 { return n.TxPackets }},
+		{interfaceMetricDesc("transmit_errors_total", "Transmit errors"), func(n domain.NetworkStats) int64 { return n.TxErrors }},
+		{interfaceMetricDesc("transmit_packets_dropped_total", "Transmitted packets dropped"), func(n domain.NetworkStats) int64 { return n.TxDropped }},
+	}
+
+	hostCpuUsageDesc = prometheus.NewDesc("godtop_host_cpu_usage_percent",
+		"CPU usage of the host in percent", nil, nil)
+	hostUsedMemoryDesc = prometheus.NewDesc("godtop_host_memory_used_bytes",
+		"Memory used on the host", nil, nil)
+	hostTotalMemoryDesc = prometheus.NewDesc("godtop_host_memory_total_bytes",
+		"Total memory of the host", nil, nil)
+	hostUsedSwapDesc = prometheus.NewDesc("godtop_host_swap_used_bytes",
+		"Swap memory used on the host", nil, nil)
+	hostTotalSwapDesc = prometheus.NewDesc("godtop_host_swap_total_bytes",
+		"Total swap memory of the host", nil, nil)
+	hostUsedStorageDesc = prometheus.NewDesc("godtop_host_storage_used_bytes",
+		"Storage used on all host partitions", nil, nil)
+	hostTotalStorageDesc = prometheus.NewDesc("godtop_host_storage_total_bytes",
+		"Total storage of all host partitions", nil, nil)
+
+	scrapeSnapshotTimeDesc = prometheus.NewDesc("godtop_scrape_snapshot_timestamp_seconds",
+		"Time the exported snapshot was collected at", nil, nil)
+	scrapeUnsampledContainersDesc = prometheus.NewDesc("godtop_scrape_unsampled_containers",
+		"Running containers of the exported snapshot whose statistics could not be sampled", nil, nil)
+	scrapeFailedEnginesDesc = prometheus.NewDesc("godtop_scrape_failed_engines",
+		"Engines of the exported snapshot whose containers could not be listed", nil, nil)
+)
+
+func interfaceMetricDesc(name string, help string) *prometheus.Desc {
+	return prometheus.NewDesc("godtop_container_network_interface_"+name,
+		help+" on a network interface of a container", append(containerLabels, "interface"), nil)
+}
+
+//metricsCollector exports the latest snapshot of the metrics collector to Prometheus,
+//scrapes do not request the engines so they stay cheap whatever the number of containers
+type metricsCollector struct {
+	collector *application.MetricsCollector
+}
+
+func newMetricsHandler(collector *application.MetricsCollector) gin.HandlerFunc {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+		&metricsCollector{collector: collector},
+	)
+
+	return gin.WrapH(promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+}
+
+func (c *metricsCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{
+		containerCpuUsageDesc, containerCpuUserDesc, containerCpuKernelDesc, containerCpuCoreDesc,
+		containerCpuQuotaDesc, containerCpuQuotaUsageDesc,
+		containerCpuPeriodsDesc, containerCpuThrottledPeriodsDesc, containerCpuThrottledSecondsDesc,
+		containerUsedMemoryDesc, containerMemoryUsageDesc,
+		containerMemoryRSSDesc, containerMemoryCacheDesc, containerMemorySwapDesc, containerMemoryLimitDesc,
+		containerRxBytesDesc, containerTxBytesDesc,
+		containerBlockReadBytesDesc, containerBlockWriteBytesDesc, containerBlockReadOpsDesc, containerBlockWriteOpsDesc,
+		containerPidsDesc, containerPidsLimitDesc,
+		hostCpuUsageDesc, hostUsedMemoryDesc, hostTotalMemoryDesc,
+		hostUsedSwapDesc, hostTotalSwapDesc, hostUsedStorageDesc, hostTotalStorageDesc,
+		scrapeSnapshotTimeDesc, scrapeUnsampledContainersDesc, scrapeFailedEnginesDesc,
+	} {
+		ch <- desc
+	}
+	for _, metric := range interfaceMetrics {
+		ch <- metric.desc
+	}
+}
+
+//Collect exports the latest snapshot, nothing is exported before the first collection
+func (c *metricsCollector) Collect(ch chan<- prometheus.Metric) {
+	snapshot := c.collector.Latest()
+	if snapshot == nil {
+		return
+	}
+
+	if snapshot.Host != nil {
+		collectHost(ch, snapshot.Host)
+	}
+
+	unsampled := 0
+	for _, item := range snapshot.Containers {
+		if item.Container.State != runningState {
+			continue
+		}
+		if item.Stats == nil {
+			unsampled++
+			continue
+		}
+		collectContainer(ch, item.Container, item.Stats)
+	}
+
+	ch <- prometheus.MustNewConstMetric(scrapeSnapshotTimeDesc, prometheus.GaugeValue, float64(snapshot.Time.UnixNano())/float64(time.Second))
+	ch <- prometheus.MustNewConstMetric(scrapeUnsampledContainersDesc, prometheus.GaugeValue, float64(unsampled))
+	ch <- prometheus.MustNewConstMetric(scrapeFailedEnginesDesc, prometheus.GaugeValue, float64(len(snapshot.FailedEngines)))
+}
+
+func collectContainer(ch chan<- prometheus.Metric, container domain.Container, stats *domain.ContainerStats) {
+	name := ""
+	if len(container.Names) > 0 {
+		name = container.Names[0]
+	}
+	labels := []string{container.ID, name, container.Engine}
+
+	ch <- prometheus.MustNewConstMetric(containerCpuUsageDesc, prometheus.GaugeValue, float64(stats.CpuUsage), labels...)
+	ch <- prometheus.MustNewConstMetric(containerCpuUserDesc, prometheus.GaugeValue, float64(stats.Cpu.User), labels...)
+	ch <- prometheus.MustNewConstMetric(containerCpuKernelDesc, prometheus.GaugeValue, float64(stats.Cpu.Kernel), labels...)
+	for core, usage := range stats.Cpu.PerCore {
+		ch <- prometheus.MustNewConstMetric(containerCpuCoreDesc, prometheus.GaugeValue, float64(usage), append(labels, strconv.Itoa(core))...)
+	}
+	if stats.Cpu.Quota > 0 {
+		ch <- prometheus.MustNewConstMetric(containerCpuQuotaDesc, prometheus.GaugeValue, stats.Cpu.Quota, labels...)
+	}
+	ch <- prometheus.MustNewConstMetric(containerCpuQuotaUsageDesc, prometheus.GaugeValue, float64(stats.Cpu.QuotaUsage), labels...)
+	ch <- prometheus.MustNewConstMetric(containerCpuPeriodsDesc, prometheus.CounterValue, float64(stats.Cpu.Periods), labels...)
+	ch <- prometheus.MustNewConstMetric(containerCpuThrottledPeriodsDesc, prometheus.CounterValue, float64(stats.Cpu.ThrottledPeriods), labels...)
+	ch <- prometheus.MustNewConstMetric(containerCpuThrottledSecondsDesc, prometheus.CounterValue, time.Duration(stats.Cpu.ThrottledTime).Seconds(), labels...)
+	ch <- prometheus.MustNewConstMetric(containerUsedMemoryDesc, prometheus.GaugeValue, float64(stats.UsedMemory), labels...)
+	ch <- prometheus.MustNewConstMetric(containerMemoryUsageDesc, prometheus.GaugeValue, float64(stats.MemoryUsage), labels...)
+	ch <- prometheus.MustNewConstMetric(containerMemoryRSSDesc, prometheus.GaugeValue, float64(stats.Memory.RSS), labels...)
+	ch <- prometheus.MustNewConstMetric(containerMemoryCacheDesc, prometheus.GaugeValue, float64(stats.Memory.Cache), labels...)
+	ch <- prometheus.MustNewConstMetric(containerMemorySwapDesc, prometheus.GaugeValue, float64(stats.Memory.Swap), labels...)
+	if stats.Memory.Limit > 0 {
+		ch <- prometheus.MustNewConstMetric(containerMemoryLimitDesc, prometheus.GaugeValue, float64(stats.Memory.Limit), labels...)
+	}
+	ch <- prometheus.MustNewConstMetric(containerRxBytesDesc, prometheus.CounterValue, float64(stats.RxBytes), labels...)
+	ch <- prometheus.MustNewConstMetric(containerTxBytesDesc, prometheus.CounterValue, float64(stats.TxBytes), labels...)
+	ch <- prometheus.MustNewConstMetric(containerBlockReadBytesDesc, prometheus.CounterValue, float64(stats.BlockIO.ReadBytes), labels...)
+	ch <- prometheus.MustNewConstMetric(containerBlockWriteBytesDesc, prometheus.CounterValue, float64(stats.BlockIO.WriteBytes), labels...)
+	ch <- prometheus.MustNewConstMetric(containerBlockReadOpsDesc, prometheus.CounterValue, float64(stats.BlockIO.ReadOps), labels...)
+	ch <- prometheus.MustNewConstMetric(containerBlockWriteOpsDesc, prometheus.CounterValue, float64(stats.BlockIO.WriteOps), labels...)
+	ch <- prometheus.MustNewConstMetric(containerPidsDesc, prometheus.GaugeValue, float64(stats.Pids.Current), labels...)
+	if stats.Pids.Limit > 0 {
+		ch <- prometheus.MustNewConstMetric(containerPidsLimitDesc, prometheus.GaugeValue, float64(stats.Pids.Limit), labels...)
+	}
+
+	for _, network := range stats.Networks {
+		for _, metric := range interfaceMetrics {
+			ch <- prometheus.MustNewConstMetric(metric.desc, prometheus.CounterValue, float64(metric.value(network)), append(labels, network.Interface)...)
+		}
+	}
+}
+
+func collectHost(ch chan<- prometheus.Metric, info *domain.HostInfo) {
+	ch <- prometheus.MustNewConstMetric(hostCpuUsageDesc, prometheus.GaugeValue, info.CpuUsage)
+	ch <- prometheus.MustNewConstMetric(hostUsedMemoryDesc, prometheus.GaugeValue, float64(info.UsedMemory))
+	ch <- prometheus.MustNewConstMetric(hostTotalMemoryDesc, prometheus.GaugeValue, float64(info.TotalMemory))
+	ch <- prometheus.MustNewConstMetric(hostUsedSwapDesc, prometheus.GaugeValue, float64(info.UsedSwapMemory))
+	ch <- prometheus.MustNewConstMetric(hostTotalSwapDesc, prometheus.GaugeValue, float64(info.TotalSwapMemory))
+	ch <- prometheus.MustNewConstMetric(hostUsedStorageDesc, prometheus.GaugeValue, float64(info.UsedStorage))
+	ch <- prometheus.MustNewConstMetric(hostTotalStorageDesc, prometheus.GaugeValue, float64(info.TotalStorage))
+}
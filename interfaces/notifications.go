@@ -0,0 +1,25 @@
+package interfaces
+
+import (
+	"godtop/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+//region Notification Handlers
+
+// getNotifications godoc
+// @Summary Retrieves the latest webhook deliveries
+// @Description /api/v2 responds in the envelope, data.deliveries holds domain.Delivery list
+// @Produce json
+// @Success 200 {array} domain.Delivery
+// @Router /notifications [get]
+func (h Handler) getNotifications(ctx *gin.Context) {
+	type payload struct {
+		Deliveries []domain.Delivery `json:"deliveries"`
+	}
+
+	Ok(ctx, payload{Deliveries: h.Notifier.Deliveries()})
+}
+
+//endregion
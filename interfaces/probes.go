@@ -0,0 +1,85 @@
+package interfaces
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+//readinessTimeout bounds the readiness checks which run concurrently
+const readinessTimeout = 3 * time.Second
+
+//closeOnShutdown ends streaming requests when the server shuts down,
+//since the server only waits for requests to finish and streams would keep it waiting until the deadline
+func (h Handler) closeOnShutdown(ctx *gin.Context) {
+	requestCtx, cancel := context.WithCancel(ctx.Request.Context())
+	defer cancel()
+
+	go func() {
+		select {
+		case <-h.streams.Done():
+			cancel()
+		case <-requestCtx.Done():
+		}
+	}()
+
+	ctx.Request = ctx.Request.WithContext(requestCtx)
+	ctx.Next()
+}
+
+//region Probe Handlers
+
+//getLiveness reports that the process is alive
+func (h Handler) getLiveness(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"status": healthOk})
+}
+
+//getReadiness reports whether every docker engine responds and the host can be sampled,
+//it fails once the server is shutting down
+func (h Handler) getReadiness(ctx *gin.Context) {
+	checks := map[string]func(context.Context) error{
+		"host": h.HostSampler.Check,
+	}
+	for _, name := range h.Engines.Names() {
+		service, _ := h.Engines.Get(name)
+		checks["docker/"+name] = service.Ping
+	}
+
+	checkCtx, cancel := context.WithTimeout(ctx.Request.Context(), readinessTimeout)
+	defer cancel()
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		status  = http.StatusOK
+		results = make(map[string]string, len(checks))
+	)
+	for name, check := range checks {
+		wg.Add(1)
+		go func(name string, check func(context.Context) error) {
+			defer wg.Done()
+			err := check(checkCtx)
+
+			mu.Lock()
+			defer mu.Unlock()
+			results[name] = healthOk
+			if err != nil {
+				results[name] = err.Error()
+				status = http.StatusServiceUnavailable
+			}
+		}(name, check)
+	}
+	wg.Wait()
+
+	if h.draining.Err() != nil {
+		results["server"] = "shutting down"
+		status = http.StatusServiceUnavailable
+	}
+
+	ctx.JSON(status, gin.H{"status": http.StatusText(status), "checks": results})
+}
+
+//endregion
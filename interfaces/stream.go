@@ -0,0 +1,114 @@
+package interfaces
+
+import (
+	"context"
+	"godtop/application"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	wsWriteTimeout      = 5 * time.Second
+	defaultHostInterval = 2 * time.Second
+	minHostInterval     = 500 * time.Millisecond
+)
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+}
+
+//region Stream Handlers
+
+// streamContainerStats godoc
+// @Summary Streams statistics of a container over WebSocket every second
+// @Description /api/v2 sends domain.ContainerStats frames which are not enveloped
+// @Param nameOrId path string true "container Name or Id"
+// @Success 101 {object} interfaces.legacyContainerStats
+// @Router /container/{nameOrId}/stats/ws [get]
+func (h Handler) streamContainerStats(ctx *gin.Context) {
+	nameOrId := ctx.Param("nameOrId")
+
+	interactor := application.ContainerInteractor{
+		Service: h.dockerService(ctx),
+	}
+
+	streamCtx, cancel := context.WithCancel(ctx.Request.Context())
+	defer cancel()
+
+	stats, err := interactor.StreamStats(streamCtx, nameOrId)
+	if err != nil {
+		Fail(ctx, err)
+		return
+	}
+
+	conn, err := upgrader.Upgrade(ctx.Writer, ctx.Request, nil)
+	if err != nil {
+		logDebug("websocket upgrade failed: %s", err)
+		return
+	}
+	defer conn.Close()
+
+	go discardIncoming(conn, cancel)
+
+	for frame := range stats {
+		conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+		if err := conn.WriteJSON(statsView(ctx, &frame)); err != nil {
+			logDebug("websocket write failed: %s", err)
+			return
+		}
+	}
+
+	conn.WriteControl(websocket.CloseMessage,
+		websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+		time.Now().Add(wsWriteTimeout))
+}
+
+// streamHostInfo godoc
+// @Summary Streams information about host system as Server-Sent Events
+// @Description /api/v2 sends domain.HostInfo events which are not enveloped
+// @Produce text/event-stream
+// @Param interval query string false "sampling interval, e.g. 2s, snapped down to 500ms, 1s, 2s, 5s, 10s, 30s or 1m"
+// @Success 200 {object} interfaces.legacyHostInfo
+// @Router /host/events [get]
+func (h Handler) streamHostInfo(ctx *gin.Context) {
+	interval := defaultHostInterval
+	if value := ctx.Query("interval"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed < minHostInterval {
+			Error(ctx, http.StatusBadRequest, err, "interval must be a duration of at least "+minHostInterval.String())
+			return
+		}
+		interval = parsed
+	}
+
+	snapshots := h.HostSampler.Subscribe(ctx.Request.Context(), interval)
+
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("X-Accel-Buffering", "no")
+	ctx.Stream(func(w io.Writer) bool {
+		info, ok := <-snapshots
+		if !ok {
+			return false
+		}
+
+		ctx.SSEvent("host", hostView(ctx, &info))
+		return true
+	})
+}
+
+//endregion
+
+//discardIncoming reads client messages until the connection is closed, then calls cancel
+func discardIncoming(conn *websocket.Conn, cancel context.CancelFunc) {
+	defer cancel()
+	for {
+		if _, _, err := conn.NextReader(); err != nil {
+			return
+		}
+	}
+}
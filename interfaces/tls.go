@@ -0,0 +1,118 @@
+package interfaces
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+//TLSFiles configures HTTPS serving, client certificates are required when ClientCAFile is set,
+//they are verified during the handshake when given and required by authenticate so probes are served without them
+type TLSFiles struct {
+	CertFile     string
+	KeyFile      string
+	ClientCAFile string
+}
+
+//certReloader serves the latest loaded certificate and client CA bundle,
+//so they can be replaced without restarting the server or dropping connections
+type certReloader struct {
+	files TLSFiles
+
+	mu          sync.RWMutex
+	certificate *tls.Certificate
+	clientCAs   *x509.CertPool
+}
+
+func newCertReloader(files TLSFiles) (*certReloader, error) {
+	reloader := &certReloader{files: files}
+	if err := reloader.reload(); err != nil {
+		return nil, err
+	}
+
+	return reloader, nil
+}
+
+func (r *certReloader) reload() error {
+	certificate, err := tls.LoadX509KeyPair(r.files.CertFile, r.files.KeyFile)
+	if err != nil {
+		return err
+	}
+
+	var clientCAs *x509.CertPool
+	if r.files.ClientCAFile != "" {
+		bundle, err := ioutil.ReadFile(r.files.ClientCAFile)
+		if err != nil {
+			return err
+		}
+
+		clientCAs = x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(bundle) {
+			return fmt.Errorf("no certificates found in %s", r.files.ClientCAFile)
+		}
+	}
+
+	r.mu.Lock()
+	r.certificate = &certificate
+	r.clientCAs = clientCAs
+	r.mu.Unlock()
+
+	return nil
+}
+
+//watchSignals reloads certificates on SIGHUP keeping the previous ones when reloading fails
+func (r *certReloader) watchSignals() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+
+	for range signals {
+		if err := r.reload(); err != nil {
+			log.Printf("tls: cannot reload certificates, keeping previous ones: %s", err)
+			continue
+		}
+		log.Printf("tls: certificates reloaded")
+	}
+}
+
+//config returns TLS configuration resolving the certificate and client CAs on every handshake
+func (r *certReloader) config() *tls.Config {
+	base := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			r.mu.RLock()
+			defer r.mu.RUnlock()
+			return r.certificate, nil
+		},
+	}
+
+	base.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		r.mu.RLock()
+		defer r.mu.RUnlock()
+
+		config := base.Clone()
+		config.GetConfigForClient = nil
+		if r.clientCAs != nil {
+			config.ClientCAs = r.clientCAs
+			config.ClientAuth = tls.VerifyClientCertIfGiven
+		}
+		return config, nil
+	}
+
+	return base
+}
+
+//validate checks that both certificate and key are given
+func (f TLSFiles) validate() error {
+	if f.CertFile == "" || f.KeyFile == "" {
+		return errors.New("tls requires both a certificate and a key file")
+	}
+
+	return nil
+}
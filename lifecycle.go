@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+//lifecycle runs background components until shutdown, signalled is done once SIGINT or SIGTERM is received
+//so the server drains its requests before the components it relies on are stopped,
+//a second signal exits immediately
+type lifecycle struct {
+	ctx       context.Context
+	stop      context.CancelFunc
+	signalled context.Context
+
+	wg      sync.WaitGroup
+	closers []func() error
+}
+
+func newLifecycle() *lifecycle {
+	ctx, stop := context.WithCancel(context.Background())
+	signalled, interrupt := context.WithCancel(context.Background())
+
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		received := <-signals
+		log.Printf("Received %s, shutting down", received)
+		interrupt()
+
+		received = <-signals
+		log.Printf("Received %s again, exiting", received)
+		os.Exit(1)
+	}()
+
+	return &lifecycle{ctx: ctx, stop: stop, signalled: signalled}
+}
+
+//start runs the component until shutdown
+func (l *lifecycle) start(run func(context.Context)) {
+	l.wg.Add(1)
+	go func() {
+		defer l.wg.Done()
+		run(l.ctx)
+	}()
+}
+
+//onStop registers release of a resource after all components stopped, resources are released in reverse order
+func (l *lifecycle) onStop(close func() error) {
+	l.closers = append(l.closers, close)
+}
+
+//shutdown stops the components waiting for them up to the timeout and releases resources,
+//it is deferred so components outlive the server draining its requests
+func (l *lifecycle) shutdown(timeout time.Duration) {
+	l.stop()
+
+	stopped := make(chan struct{})
+	go func() {
+		l.wg.Wait()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(timeout):
+		log.Printf("Background components did not stop in %s", timeout)
+	}
+
+	for i := len(l.closers) - 1; i >= 0; i-- {
+		if err := l.closers[i](); err != nil {
+			log.Printf("Shutdown: %s", err)
+		}
+	}
+}
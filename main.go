@@ -1,14 +1,23 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"godtop/application"
+	"godtop/config"
+	"godtop/domain"
 	"godtop/infrastructure"
 	"godtop/interfaces"
 	"log"
+	"os"
+	"strings"
 )
 
 // @title Godtop
 // @version 1.0
-// @description Docker graphical activity monitor
+// @description Docker graphical activity monitor, every route is also served under /api/v2 with responses in an envelope of data, meta and errors
 
 // @contact.name Aleksey Fishchev
 // @contact.email mrfishchev@seniorvlogger.com
@@ -18,12 +27,308 @@ import (
 
 // @BasePath /api
 func main() {
-	handler := interfaces.Handler{
-		DockerService: infrastructure.CreateDockerService(),
-		HostService:   infrastructure.CreateHostService(),
+	args := os.Args[1:]
+	if len(args) >= 2 && args[0] == "config" && args[1] == "validate" {
+		if err := validateConfig(args[2:]); err != nil {
+			exit(err)
+		}
+		fmt.Println("configuration is valid")
+		return
+	}
+
+	run := serve
+	name := "godtop"
+	if len(args) > 0 && (args[0] == "agent" || args[0] == "server") {
+		if args[0] == "agent" {
+			run = runAgent
+		}
+		name += " " + args[0]
+		args = args[1:]
+	}
+
+	cfg, err := config.Parse(name, args)
+	if err == nil {
+		err = cfg.Validate()
+	}
+	if err != nil {
+		exit(err)
 	}
 
-	if err := handler.RunServer(8080); err != nil {
+	if err := run(cfg); err != nil {
 		log.Fatal(err)
 	}
 }
+
+//serve starts the background components and the server
+func serve(cfg *config.Config) error {
+	lifecycle := newLifecycle()
+	defer lifecycle.shutdown(cfg.ShutdownTimeout)
+
+	engines, monitors, err := createEngines(cfg.Docker)
+	if err != nil {
+		return err
+	}
+	for _, monitor := range monitors {
+		lifecycle.start(monitor)
+	}
+	hostService := infrastructure.CreateHostService()
+
+	metricsStore, err := createMetricsStore(lifecycle, cfg.Metrics)
+	if err != nil {
+		return err
+	}
+
+	collector := &application.MetricsCollector{
+		Engines:     engines,
+		HostService: hostService,
+		Store:       metricsStore,
+		Interval:    cfg.Metrics.Interval,
+	}
+	lifecycle.start(collector.Run)
+
+	journal := application.NewEventJournal(engines, cfg.Events.Capacity)
+	lifecycle.start(journal.Run)
+
+	notifications := cfg.Notifications
+	notifier, err := application.NewNotifier(infrastructure.CreateWebhookSender(notifications.Timeout),
+		notifications.Receivers, notifications.Retries, notifications.Backoff)
+	if err != nil {
+		return err
+	}
+	lifecycle.start(func(ctx context.Context) {
+		notifier.Run(ctx, journal)
+	})
+
+	alerts, err := application.NewAlertEngine(collector, cfg.Alerts.Interval, cfg.Alerts.Rules)
+	if err != nil {
+		return err
+	}
+	alerts.Notify = notifier.NotifyAlert
+	lifecycle.start(alerts.Run)
+
+	fleet := application.NewFleet(cfg.Fleet.Node, cfg.Fleet.StaleAfter)
+	lifecycle.start(fleet.Run)
+	lifecycle.start(func(ctx context.Context) {
+		fleet.Follow(ctx, collector)
+	})
+
+	authenticators, err := createAuthenticators(cfg.Auth, cfg.TLS)
+	if err != nil {
+		return err
+	}
+
+	accessPolicy, err := createAccessPolicy(cfg.RBAC)
+	if err != nil {
+		return err
+	}
+
+	handler := interfaces.Handler{
+		Engines:      engines,
+		HostService:  hostService,
+		HostSampler:  application.NewHostSampler(hostService),
+		MetricsStore: metricsStore,
+		Collector:    collector,
+		EventJournal: journal,
+		AlertEngine:  alerts,
+		Notifier:     notifier,
+		Fleet:        fleet,
+
+		Config:          cfg,
+		Debug:           cfg.Debug,
+		ShutdownTimeout: cfg.ShutdownTimeout,
+		ShutdownDelay:   cfg.ShutdownDelay,
+
+		Authenticators: authenticators,
+		AccessPolicy:   accessPolicy,
+	}
+
+	if cfg.Exporters.Prometheus.Enabled {
+		handler.MetricsPath = cfg.Exporters.Prometheus.Path
+	}
+
+	if cfg.TLS.Cert != "" {
+		handler.TLS = &interfaces.TLSFiles{
+			CertFile:     cfg.TLS.Cert,
+			KeyFile:      cfg.TLS.Key,
+			ClientCAFile: cfg.TLS.ClientCA,
+		}
+	}
+
+	return handler.RunServer(lifecycle.signalled, cfg.Listen)
+}
+
+//runAgent collects snapshots of the engines and the host and pushes them to the fleet server
+func runAgent(cfg *config.Config) error {
+	if err := cfg.ValidateAgent(); err != nil {
+		return err
+	}
+
+	lifecycle := newLifecycle()
+	defer lifecycle.shutdown(cfg.ShutdownTimeout)
+
+	engines, monitors, err := createEngines(cfg.Docker)
+	if err != nil {
+		return err
+	}
+	for _, monitor := range monitors {
+		lifecycle.start(monitor)
+	}
+
+	agent := &application.Agent{
+		Collector: &application.MetricsCollector{
+			Engines:     engines,
+			HostService: infrastructure.CreateHostService(),
+			Interval:    cfg.Metrics.Interval,
+		},
+		URL:     strings.TrimSuffix(cfg.Agent.Server, "/") + "/api/fleet/ingest",
+		Node:    cfg.Fleet.Node,
+		Headers: map[string]string{},
+	}
+
+	if cfg.Agent.Token != "" {
+		agent.Headers["Authorization"] = "Bearer " + cfg.Agent.Token
+	}
+
+	if cfg.Agent.TLSCA != "" || cfg.Agent.TLSCert != "" {
+		if agent.Sender, err = infrastructure.CreateTLSWebhookSender(cfg.Agent.Timeout, cfg.Agent.TLSCA, cfg.Agent.TLSCert, cfg.Agent.TLSKey); err != nil {
+			return err
+		}
+	} else {
+		agent.Sender = infrastructure.CreateWebhookSender(cfg.Agent.Timeout)
+	}
+
+	log.Printf("Agent reporting to %s as %s", cfg.Agent.Server, cfg.Fleet.Node)
+	agent.Run(lifecycle.signalled)
+	return nil
+}
+
+//validateConfig checks the configuration and builds the components which reject invalid settings without starting them
+func validateConfig(args []string) error {
+	cfg, err := config.Parse("godtop config validate", args)
+	if err != nil {
+		return err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	if _, _, err := createEngines(cfg.Docker); err != nil {
+		return err
+	}
+
+	if cfg.Agent.Server != "" {
+		if err := cfg.ValidateAgent(); err != nil {
+			return err
+		}
+	}
+
+	if _, err := application.NewAlertEngine(nil, cfg.Alerts.Interval, cfg.Alerts.Rules); err != nil {
+		return err
+	}
+
+	if _, err := application.NewNotifier(nil, cfg.Notifications.Receivers, cfg.Notifications.Retries, cfg.Notifications.Backoff); err != nil {
+		return err
+	}
+
+	if _, err := createAuthenticators(cfg.Auth, cfg.TLS); err != nil {
+		return err
+	}
+
+	_, err = createAccessPolicy(cfg.RBAC)
+	return err
+}
+
+//exit reports the error and exits, asking for help is not an error
+func exit(err error) {
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
+
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
+
+//createEngines creates clients of the configured docker engines, background components watch all of them,
+//monitors keep checking the connections and close the clients when their context is done
+func createEngines(docker config.DockerConfig) (*application.Engines, []func(context.Context), error) {
+	engines := application.NewEngines()
+	var monitors []func(context.Context)
+	for _, endpoint := range docker.Endpoints() {
+		service, err := infrastructure.CreateDockerService(endpoint)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := engines.Add(endpoint.Name, service); err != nil {
+			return nil, nil, err
+		}
+		monitors = append(monitors, service.Run)
+	}
+
+	return engines, monitors, nil
+}
+
+//createMetricsStore persists metrics under the data directory when it is set, otherwise keeps them in memory
+func createMetricsStore(lifecycle *lifecycle, metrics config.MetricsConfig) (domain.MetricsStore, error) {
+	if metrics.DataDir == "" {
+		return infrastructure.CreateMemoryStore(int(metrics.History/metrics.Interval), metrics.History), nil
+	}
+
+	store, err := infrastructure.CreateBoltStore(metrics.DataDir, metrics.Retention)
+	if err != nil {
+		return nil, err
+	}
+	lifecycle.start(store.Run)
+	lifecycle.onStop(store.Close)
+
+	return store, nil
+}
+
+//createAccessPolicy returns nil when there are no bindings, so access control is disabled
+func createAccessPolicy(rbac config.RBACConfig) (*application.AccessPolicy, error) {
+	if len(rbac.Bindings) == 0 {
+		return nil, nil
+	}
+
+	return application.NewAccessPolicy(rbac.Bindings)
+}
+
+//createAuthenticators returns authenticators of the configured methods,
+//the subject of client certificates is the principal when a client CA is configured and cert is not listed
+func createAuthenticators(auth config.AuthConfig, tls config.TLSConfig) ([]interfaces.Authenticator, error) {
+	var result []interfaces.Authenticator
+	certListed := false
+	for _, method := range auth.Methods {
+		switch method {
+		case "token":
+			tokens := interfaces.TokenAuthenticator{}
+			for _, token := range auth.Tokens {
+				if token.Name == "" || token.Token == "" {
+					return nil, errors.New("auth tokens must have a name and a token")
+				}
+				tokens[token.Token] = token.Name
+			}
+			result = append(result, tokens)
+		case "cert":
+			if tls.ClientCA == "" {
+				return nil, errors.New("cert auth method requires tls.clientCA")
+			}
+			result = append(result, interfaces.CertAuthenticator{})
+			certListed = true
+		case "basic":
+			verifier, err := infrastructure.LoadHtpasswd(auth.Htpasswd)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, interfaces.BasicAuthenticator{Verifier: verifier})
+		default:
+			return nil, fmt.Errorf("unknown auth method %q, expected token, basic or cert", method)
+		}
+	}
+
+	if tls.ClientCA != "" && !certListed {
+		result = append(result, interfaces.CertAuthenticator{})
+	}
+
+	return result, nil
+}